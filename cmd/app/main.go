@@ -1,25 +1,319 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"math/big"
+	"os"
 	"polymarket/internal/polymarket"
 	"polymarket/internal/web3"
+	"polymarket/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 func main() {
-	const RPC = "https://rpc.ankr.com/polygon"
-	const privateKey = "0x85e8f5ba193801d718f7c277f15f9a4c732bd061d96d8720fe606d2107ef8778"
+	rpc := flag.String("rpc", "https://rpc.ankr.com/polygon", "polygon RPC url, or a comma separated list to fail over between")
+	keystorePath := flag.String("keystore", "keystore", "keystore file or directory")
+	signerURL := flag.String("signer", "", "remote signer url, used instead of -keystore; token from "+utils.SignerTokenEnv)
+	gasMode := flag.String("gas", "history", "gas strategy: suggested or history")
+	gasHeadroom := flag.Float64("gas-headroom", 2, "max fee covers this many times the base fee")
+	gasCap := flag.Float64("gas-cap-gwei", 0, "never pay more than this max fee, 0 = no cap")
+	tipOverride := flag.Float64("tip-gwei", 0, "fixed priority fee, used with -max-fee-gwei")
+	maxFeeOverride := flag.Float64("max-fee-gwei", 0, "fixed max fee, used with -tip-gwei")
+	bumpAfter := flag.Duration("bump-after", 90*time.Second, "replace txs pending longer than this, 0 = never")
+	step := flag.String("step", "create", "what to do for every wallet: create (account), approve (trading approvals), redeem (resolved positions), split, merge or convert (positions), deposit or withdraw (USDC.e), api-key (CLOB credentials)")
+	amount := flag.String("amount", "", "USDC.e amount to deposit, withdraw, split, merge or convert, e.g. 12.5")
+	conditionID := flag.String("condition", "", "condition id to split or merge")
+	marketID := flag.String("market", "", "neg-risk market id to convert positions of")
+	questions := flag.String("questions", "", "comma separated question indexes of -market whose NO to convert")
+	withdrawTo := flag.String("to", "", "withdrawal destination, defaults to the wallet's own address")
+	allowlist := flag.String("allowlist", "withdraw_allowlist.txt", "file listing addresses withdrawals may go to besides the wallet itself, one per line")
+	clobCreds := flag.String("clob-creds", "clob_creds", "directory keeping CLOB API credentials, encrypted with the passphrase from "+utils.PassphraseEnv+" or a prompt")
+	confirmations := flag.Uint64("confirmations", 2, "blocks to wait after a deposit or withdrawal is mined")
+	flag.Parse()
+
+	if (*tipOverride > 0) != (*maxFeeOverride > 0) {
+		log.Fatalf("-tip-gwei and -max-fee-gwei must be set together")
+	}
+	if *tipOverride > *maxFeeOverride {
+		log.Fatalf("-tip-gwei %v is above -max-fee-gwei %v", *tipOverride, *maxFeeOverride)
+	}
+
+	var gas web3.GasStrategy
+	switch {
+	case *tipOverride > 0:
+		gas = web3.FixedGas{TipGwei: *tipOverride, MaxFeeGwei: *maxFeeOverride}
+	case *gasMode == "suggested":
+		gas = web3.HeadroomGas{Strategy: web3.SuggestedGas{}, Multiplier: *gasHeadroom}
+	case *gasMode == "history":
+		gas = web3.HeadroomGas{Strategy: web3.FeeHistoryGas{Blocks: 20, Percentile: 60}, Multiplier: *gasHeadroom}
+	default:
+		log.Fatalf("unknown -gas %q, use suggested or history", *gasMode)
+	}
+	if *gasCap > 0 {
+		gas = web3.CappedGas{Strategy: gas, MaxFeeGwei: *gasCap}
+	}
+
+	wallets, err := loadWallets(*rpc, *keystorePath, *signerURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, wallet := range wallets {
+		wallet.Gas = gas
+		if *bumpAfter > 0 {
+			wallet.Bump = &web3.BumpPolicy{After: *bumpAfter, Percent: 15}
+			if *gasCap > 0 {
+				wallet.Bump.MaxFeePerGas = web3.Gwei(*gasCap)
+			}
+		}
+	}
 
 	polyC := polymarket.New()
-	wallet, err := web3.New(RPC, privateKey)
+	credStore := &polymarket.FileStore{Dir: *clobCreds}
+	if *step == "api-key" {
+		credStore.Passphrase, err = utils.ReadPassphrase("CLOB credentials passphrase: ")
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	for _, wallet := range wallets {
+		ampCook := polyC.GenerateAMPCookie()
+
+		switch *step {
+		case "create":
+			err = polymarket.CreateAccount(polyC, wallet, ampCook)
+		case "approve":
+			err = approve(polyC, wallet, ampCook)
+		case "redeem":
+			err = redeem(polyC, wallet, ampCook)
+		case "split", "merge":
+			err = splitOrMerge(polyC, wallet, ampCook, *step, *conditionID, *amount)
+		case "convert":
+			err = convert(polyC, wallet, ampCook, *marketID, *questions, *amount)
+		case "api-key":
+			err = apiKey(polymarket.NewClob(wallet, credStore))
+		case "deposit":
+			err = deposit(wallet, *amount, *confirmations)
+		case "withdraw":
+			err = withdraw(wallet, *amount, *withdrawTo, *allowlist, *confirmations)
+		default:
+			log.Fatalf("unknown step %q", *step)
+		}
+		switch {
+		case errors.Is(err, polymarket.ErrTxPending):
+			log.Printf("%s | %v, check it before running %s again", wallet.Address, err, *step)
+		case err != nil:
+			fmt.Println(err)
+		}
+	}
+}
+
+func approve(polyC *polymarket.Client, wallet *web3.Wallet, ampCook string) error {
+	session, err := polymarket.SignIn(polyC, wallet, ampCook)
+	if err != nil {
+		return err
+	}
+
+	_, err = polymarket.SetupApprovals(polyC, wallet, session)
+
+	return err
+}
+
+func loadWallets(rpc, keystorePath, signerURL string) ([]*web3.Wallet, error) {
+	client, err := web3.Dial(rpc)
+	if err != nil {
+		return nil, err
+	}
+
+	if multi, ok := client.(*web3.MultiClient); ok {
+		go multi.Run(context.Background(), 30*time.Second)
+	}
+
+	var wallets []*web3.Wallet
+
+	if signerURL != "" {
+		signers, err := web3.DialSigner(context.Background(), signerURL, os.Getenv(utils.SignerTokenEnv))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, signer := range signers {
+			wallets = append(wallets, web3.NewWithBackend(client, signer))
+		}
+	} else {
+		files, err := web3.KeystoreFiles(keystorePath)
+		if err != nil {
+			return nil, err
+		}
+
+		passphrase, err := utils.ReadPassphrase("Keystore passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			pKey, err := web3.LoadKeystore(file, passphrase)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			wallets = append(wallets, web3.NewWithBackend(client, web3.NewKeySigner(pKey)))
+		}
+	}
+
+	for _, wallet := range wallets {
+		if err := wallet.SelectNetwork(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
+	return wallets, nil
+}
+
+func redeem(polyC *polymarket.Client, wallet *web3.Wallet, ampCook string) error {
+	session, err := polymarket.SignIn(polyC, wallet, ampCook)
+	if err != nil {
+		return err
+	}
+
+	_, err = polymarket.Redeem(polyC, wallet, session)
+
+	return err
+}
+
+func splitOrMerge(polyC *polymarket.Client, wallet *web3.Wallet, ampCook, step, conditionID, amount string) error {
+	value, err := web3.ParseUSDC(amount)
+	if err != nil {
+		return err
+	}
+
+	condition, err := parseHash(conditionID)
+	if err != nil {
+		return err
+	}
+
+	session, err := polymarket.SignIn(polyC, wallet, ampCook)
 	if err != nil {
-		fmt.Println(err)
+		return err
+	}
+
+	if step == "merge" {
+		return polymarket.MergePositions(polyC, wallet, session, condition, value)
 	}
 
-	ampCook := polyC.GenerateAMPCookie()
+	return polymarket.SplitPosition(polyC, wallet, session, condition, value)
+}
+
+func convert(polyC *polymarket.Client, wallet *web3.Wallet, ampCook, marketID, questions, amount string) error {
+	value, err := web3.ParseUSDC(amount)
+	if err != nil {
+		return err
+	}
+
+	market, err := parseHash(marketID)
+	if err != nil {
+		return err
+	}
+
+	indexSet := new(big.Int)
+	for _, question := range strings.Split(questions, ",") {
+		index, err := strconv.ParseUint(strings.TrimSpace(question), 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid question index %q", question)
+		}
+		indexSet.SetBit(indexSet, int(index), 1)
+	}
+
+	session, err := polymarket.SignIn(polyC, wallet, ampCook)
+	if err != nil {
+		return err
+	}
+
+	return polymarket.ConvertPositions(polyC, wallet, session, market, indexSet, value)
+}
+
+func parseHash(s string) (common.Hash, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid id %q, want 32 bytes hex", s)
+	}
+
+	return common.BytesToHash(b), nil
+}
+
+func apiKey(clob *polymarket.ClobClient) error {
+	creds, err := clob.ApiCreds()
+	if err != nil {
+		return err
+	}
 
-	err = polymarket.CreateAccount(polyC, wallet, ampCook)
+	keys, err := clob.ListApiKeys()
 	if err != nil {
-		fmt.Println(err)
+		return err
 	}
+
+	log.Printf("CLOB api key %s | %s | %d keys", creds.Key, clob.Wallet.Address, len(keys))
+
+	return nil
+}
+
+func deposit(wallet *web3.Wallet, amount string, confirmations uint64) error {
+	value, err := web3.ParseUSDC(amount)
+	if err != nil {
+		return err
+	}
+
+	result, err := wallet.USDC().Deposit(context.Background(), value, confirmations)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Deposited %s USDC.e | %s | EOA %s | proxy %s", value, result.Tx.Hash, result.Balances.EOA, result.Balances.Proxy)
+
+	return nil
+}
+
+func withdraw(wallet *web3.Wallet, amount, to, allowlist string, confirmations uint64) error {
+	value, err := web3.ParseUSDC(amount)
+	if err != nil {
+		return err
+	}
+
+	destination := wallet.Address
+	if to != "" {
+		if !common.IsHexAddress(to) {
+			return fmt.Errorf("invalid withdrawal destination %q", to)
+		}
+		destination = common.HexToAddress(to)
+	}
+
+	var allowed []common.Address
+	if destination != wallet.Address {
+		allowed, err = web3.LoadAllowlist(allowlist)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no allowlist %s, withdrawals can only go to the wallet itself", allowlist)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err := wallet.USDC().Withdraw(context.Background(), destination, value, allowed, confirmations)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Withdrew %s USDC.e to %s | %s | EOA %s | proxy %s", value, destination, result.Tx.Hash, result.Balances.EOA, result.Balances.Proxy)
+
+	return nil
 }
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"polymarket/internal/web3"
+	"polymarket/utils"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+const usage = `usage:
+  keystore new    [-dir keystore] [-n 1]
+  keystore import [-dir keystore] [-file keys.txt]
+
+import reads one hex private key per line from -file, or prompts for a
+single key when -file is not set. Delete the plain key file afterwards.
+The passphrase is taken from ` + utils.PassphraseEnv + ` or prompted.`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dir := fs.String("dir", "keystore", "keystore directory")
+	count := fs.Int("n", 1, "number of keys to create")
+	file := fs.String("file", "", "file with hex private keys, one per line")
+	_ = fs.Parse(os.Args[2:])
+
+	var err error
+	switch os.Args[1] {
+	case "new":
+		err = newKeys(*dir, *count)
+	case "import":
+		err = importKeys(*dir, *file)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newKeys(dir string, count int) error {
+	passphrase, err := newPassphrase()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		address, err := web3.CreateKeystore(dir, passphrase)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Created %s", address)
+	}
+
+	return nil
+}
+
+func importKeys(dir, file string) error {
+	var keys []string
+
+	if file == "" {
+		key, err := utils.ReadSecret("Private key: ")
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	} else {
+		f, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("can't open keys file: %w", err)
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			keys = append(keys, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("error when read keys file: %w", err)
+		}
+	}
+
+	passphrase, err := newPassphrase()
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		address, err := web3.ImportKeystore(dir, key, passphrase)
+		if err != nil {
+			return fmt.Errorf("key #%d: %w", i+1, err)
+		}
+
+		log.Printf("Imported %s", address)
+	}
+
+	return nil
+}
+
+func newPassphrase() (string, error) {
+	if passphrase, ok := os.LookupEnv(utils.PassphraseEnv); ok {
+		return passphrase, nil
+	}
+
+	passphrase, err := utils.ReadSecret("Keystore passphrase: ")
+	if err != nil {
+		return "", err
+	}
+
+	confirm, err := utils.ReadSecret("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+
+	if passphrase != confirm {
+		return "", errors.New("passphrases do not match")
+	}
+
+	return passphrase, nil
+}
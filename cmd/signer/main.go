@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"net"
+	"net/http"
+	"os"
+	"polymarket/internal/web3"
+	"polymarket/utils"
+
+	"github.com/charmbracelet/log"
+)
+
+// signer serves the keys of a keystore over JSON-RPC so the app can run
+// with -signer and never hold them itself. Clients authenticate with the
+// bearer token from POLY_SIGNER_TOKEN.
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8550", "listen address")
+	keystorePath := flag.String("keystore", "keystore", "keystore file or directory")
+	public := flag.Bool("public", false, "allow listening on a non-loopback address")
+	allowlist := flag.String("allowlist", "withdraw_allowlist.txt", "file listing addresses USDC may be sent to besides the accounts and their Safes, one per line")
+	flag.Parse()
+
+	token := os.Getenv(utils.SignerTokenEnv)
+	if len(token) < 32 {
+		log.Fatalf("set %s to a random token of at least 32 characters", utils.SignerTokenEnv)
+	}
+
+	host, _, err := net.SplitHostPort(*addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if ip := net.ParseIP(host); (ip == nil || !ip.IsLoopback()) && host != "localhost" && !*public {
+		log.Fatalf("refusing to listen on %s, pass -public to expose the signer", *addr)
+	}
+
+	payees, err := web3.LoadAllowlist(*allowlist)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatal(err)
+	}
+
+	files, err := web3.KeystoreFiles(*keystorePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	passphrase, err := utils.ReadPassphrase("Keystore passphrase: ")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var signers []web3.Signer
+	for _, file := range files {
+		pKey, err := web3.LoadKeystore(file, passphrase)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		signer := web3.NewKeySigner(pKey)
+		signers = append(signers, signer)
+		log.Printf("Loaded %s", signer.Address())
+	}
+
+	server, err := web3.NewSignerServer(web3.SignerPolicy{Token: token, Network: web3.Polygon, Payees: payees}, signers...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Signer listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
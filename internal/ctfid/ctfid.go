@@ -0,0 +1,149 @@
+// Package ctfid derives the identifiers of the Gnosis Conditional Tokens
+// Framework: condition, collection and position (ERC-1155 token) IDs.
+package ctfid
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// fieldP is the alt_bn128 base field modulus; the curve is y² = x³ + 3.
+	fieldP = fromDecimal("21888242871839275222246405745257275088696311157297823662689037894645226208583")
+	curveB = big.NewInt(3)
+
+	// sqrtExp is (P+1)/4, square roots are a single exponentiation as P ≡ 3 mod 4.
+	sqrtExp = new(big.Int).Rsh(new(big.Int).Add(fieldP, big.NewInt(1)), 2)
+
+	bit254 = new(big.Int).Lsh(big.NewInt(1), 254)
+	bit255 = new(big.Int).Lsh(big.NewInt(1), 255)
+)
+
+// ConditionID is keccak256(oracle, questionID, outcomeSlotCount).
+func ConditionID(oracle common.Address, questionID common.Hash, outcomeSlotCount uint64) common.Hash {
+	return crypto.Keccak256Hash(
+		oracle.Bytes(),
+		questionID.Bytes(),
+		common.LeftPadBytes(new(big.Int).SetUint64(outcomeSlotCount).Bytes(), 32),
+	)
+}
+
+// CollectionID maps (conditionID, indexSet) to a point of alt_bn128 and adds
+// the parent collection's point to it, so collections combine in any order.
+// A zero parentCollectionID is the root collection.
+func CollectionID(parentCollectionID, conditionID common.Hash, indexSet *big.Int) (common.Hash, error) {
+	if indexSet == nil || indexSet.Sign() <= 0 {
+		return common.Hash{}, fmt.Errorf("invalid index set %v", indexSet)
+	}
+
+	x1 := new(big.Int).SetBytes(crypto.Keccak256(conditionID.Bytes(), common.LeftPadBytes(indexSet.Bytes(), 32)))
+	odd := x1.Cmp(bit255) >= 0
+
+	var y1, yy *big.Int
+	for {
+		x1.Add(x1, big.NewInt(1))
+		x1.Mod(x1, fieldP)
+		yy = curveY2(x1)
+		y1 = new(big.Int).Exp(yy, sqrtExp, fieldP)
+		if new(big.Int).Exp(y1, big.NewInt(2), fieldP).Cmp(yy) == 0 {
+			break
+		}
+	}
+	if odd != (y1.Bit(0) == 1) {
+		y1.Sub(fieldP, y1)
+	}
+
+	x2 := parentCollectionID.Big()
+	if x2.Sign() != 0 {
+		parentOdd := x2.Bit(254) == 1 || x2.Bit(255) == 1
+		x2.SetBit(x2, 255, 0)
+		x2.SetBit(x2, 254, 0)
+
+		yy = curveY2(x2)
+		y2 := new(big.Int).Exp(yy, sqrtExp, fieldP)
+		if parentOdd != (y2.Bit(0) == 1) {
+			y2.Sub(fieldP, y2)
+		}
+		if new(big.Int).Exp(y2, big.NewInt(2), fieldP).Cmp(yy) != 0 {
+			return common.Hash{}, fmt.Errorf("invalid parent collection id %s", parentCollectionID)
+		}
+
+		var err error
+		x1, y1, err = addPoints(x1, y1, x2, y2)
+		if err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	if y1.Bit(0) == 1 {
+		x1.Xor(x1, bit254)
+	}
+
+	return common.BigToHash(x1), nil
+}
+
+// PositionID is the ERC-1155 token id of collateral in a collection,
+// keccak256(collateral, collectionID).
+func PositionID(collateral common.Address, collectionID common.Hash) *big.Int {
+	return new(big.Int).SetBytes(crypto.Keccak256(collateral.Bytes(), collectionID.Bytes()))
+}
+
+// OutcomePositionID is the position id of a single outcome of a condition
+// in the root collection, the token id Polymarket markets trade.
+func OutcomePositionID(collateral common.Address, conditionID common.Hash, outcomeIndex uint) (*big.Int, error) {
+	collectionID, err := CollectionID(common.Hash{}, conditionID, new(big.Int).Lsh(big.NewInt(1), outcomeIndex))
+	if err != nil {
+		return nil, err
+	}
+
+	return PositionID(collateral, collectionID), nil
+}
+
+func curveY2(x *big.Int) *big.Int {
+	yy := new(big.Int).Exp(x, big.NewInt(3), fieldP)
+	yy.Add(yy, curveB)
+
+	return yy.Mod(yy, fieldP)
+}
+
+// addPoints adds two affine points of alt_bn128, as the ecAdd precompile.
+func addPoints(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int, error) {
+	var lambda *big.Int
+
+	if x1.Cmp(x2) == 0 {
+		if new(big.Int).Add(y1, y2).Mod(new(big.Int).Add(y1, y2), fieldP).Sign() == 0 {
+			return nil, nil, fmt.Errorf("collection ids add up to the point at infinity")
+		}
+		// 3x² / 2y
+		num := new(big.Int).Mul(x1, x1)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(y1, 1)
+		lambda = num.Mul(num, den.ModInverse(den.Mod(den, fieldP), fieldP))
+	} else {
+		// (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(y2, y1)
+		den := new(big.Int).Sub(x2, x1)
+		lambda = num.Mul(num, den.ModInverse(den.Mod(den, fieldP), fieldP))
+	}
+	lambda.Mod(lambda, fieldP)
+
+	x3 := new(big.Int).Mul(lambda, lambda)
+	x3.Sub(x3, x1).Sub(x3, x2).Mod(x3, fieldP)
+
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, lambda).Sub(y3, y1).Mod(y3, fieldP)
+
+	return x3, y3, nil
+}
+
+func fromDecimal(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("bad constant " + s)
+	}
+
+	return n
+}
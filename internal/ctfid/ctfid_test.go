@@ -0,0 +1,171 @@
+package ctfid
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+)
+
+// usdc is the collateral of Polymarket binary markets on Polygon.
+var usdc = common.HexToAddress("0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174")
+
+// negRiskCollateral is the neg-risk adapter's wrapped USDC.e on Polygon.
+var negRiskCollateral = common.HexToAddress("0x3A3BD7bb9528E159577F7C2e685CC81A765002E2")
+
+// publishedMarkets are markets with the condition id and the YES and NO
+// token ids the CLOB publishes for them.
+var publishedMarkets = []struct {
+	name       string
+	collateral common.Address
+	// oracle prepared conditionID for questionID.
+	oracle      common.Address
+	questionID  common.Hash
+	conditionID common.Hash
+	tokenIDs    [2]string
+}{
+	{
+		name:        "Presidential Election Winner 2024: Donald Trump (neg risk)",
+		collateral:  negRiskCollateral,
+		oracle:      common.HexToAddress("0xd91E80cF2E7be2e162c6513ceD06f1dD0dA35296"),
+		questionID:  common.HexToHash("0xe3b1bc389210504ebcb9cffe4b0ed06ccac50561e0f24abb6379984cec030f00"),
+		conditionID: common.HexToHash("0xdd22472e552920b8438158ea7238bfadfa4f736aa4cee91a6b86c39ead110917"),
+		tokenIDs: [2]string{
+			"21742633143463906290569050155826241533067272736897614950488156847949938836455",
+			"48331043336612883890938759509493159234755048973500640148014422747788308965732",
+		},
+	},
+}
+
+func TestPublishedMarkets(t *testing.T) {
+	for _, market := range publishedMarkets {
+		if got := ConditionID(market.oracle, market.questionID, 2); got != market.conditionID {
+			t.Errorf("%s: condition %s, want %s", market.name, got, market.conditionID)
+		}
+
+		for i, want := range market.tokenIDs {
+			got, err := OutcomePositionID(market.collateral, market.conditionID, uint(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != want {
+				t.Errorf("%s outcome %d: token %s, want %s", market.name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestConditionID(t *testing.T) {
+	oracle := common.HexToAddress("0xd91E80cF2E7be2e162c6513ceD06f1dD0dA35296")
+	questionID := common.HexToHash("0x1234")
+
+	// abi.encodePacked(address, bytes32, uint256)
+	packed := append(append(oracle.Bytes(), questionID.Bytes()...), common.BigToHash(big.NewInt(2)).Bytes()...)
+	if len(packed) != 20+32+32 {
+		t.Fatalf("packed %d bytes", len(packed))
+	}
+
+	if got, want := ConditionID(oracle, questionID, 2), crypto.Keccak256Hash(packed); got != want {
+		t.Errorf("condition id %s, want %s", got, want)
+	}
+}
+
+// decodeCollection turns a collection id back into its alt_bn128 point as
+// the ecAdd precompile takes it.
+func decodeCollection(t *testing.T, id common.Hash) *bn256.G1 {
+	t.Helper()
+
+	x := id.Big()
+	odd := x.Bit(254) == 1
+	x.SetBit(x, 254, 0)
+
+	y := new(big.Int).Exp(curveY2(x), sqrtExp, fieldP)
+	if new(big.Int).Exp(y, big.NewInt(2), fieldP).Cmp(curveY2(x)) != 0 {
+		t.Fatalf("collection %s is not on the curve", id)
+	}
+	if odd != (y.Bit(0) == 1) {
+		y.Sub(fieldP, y)
+	}
+
+	point := new(bn256.G1)
+	if _, err := point.Unmarshal(append(common.BigToHash(x).Bytes(), common.BigToHash(y).Bytes()...)); err != nil {
+		t.Fatalf("collection %s: %v", id, err)
+	}
+
+	return point
+}
+
+// TestCollectionID checks the curve arithmetic against go-ethereum's
+// alt_bn128 implementation, the one behind the ecAdd precompile.
+func TestCollectionID(t *testing.T) {
+	condition := crypto.Keccak256Hash([]byte("condition"))
+	other := crypto.Keccak256Hash([]byte("other condition"))
+
+	for indexSet := int64(1); indexSet <= 3; indexSet++ {
+		child, err := CollectionID(common.Hash{}, condition, big.NewInt(indexSet))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if child.Big().Bit(255) != 0 {
+			t.Errorf("collection %s has bit 255 set", child)
+		}
+
+		parent, err := CollectionID(common.Hash{}, other, big.NewInt(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nested, err := CollectionID(parent, condition, big.NewInt(indexSet))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := new(bn256.G1).Add(decodeCollection(t, parent), decodeCollection(t, child))
+		if got := decodeCollection(t, nested); string(got.Marshal()) != string(want.Marshal()) {
+			t.Errorf("index set %d: nested collection %s is not the sum of its parts", indexSet, nested)
+		}
+
+		// Collections combine in any order.
+		reversed, err := CollectionID(child, other, big.NewInt(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reversed != nested {
+			t.Errorf("index set %d: %s != %s", indexSet, reversed, nested)
+		}
+	}
+
+	for _, indexSet := range []*big.Int{nil, big.NewInt(0), big.NewInt(-1)} {
+		if _, err := CollectionID(common.Hash{}, condition, indexSet); err == nil {
+			t.Errorf("index set %v was accepted", indexSet)
+		}
+	}
+}
+
+func TestOutcomePositionID(t *testing.T) {
+	condition := crypto.Keccak256Hash([]byte("condition"))
+
+	yes, err := OutcomePositionID(usdc, condition, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	no, err := OutcomePositionID(usdc, condition, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if yes.Cmp(no) == 0 {
+		t.Fatal("outcomes share a token id")
+	}
+
+	collection, err := CollectionID(common.Hash{}, condition, big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// abi.encodePacked(address, bytes32)
+	want := new(big.Int).SetBytes(crypto.Keccak256(append(usdc.Bytes(), collection.Bytes()...)))
+	if no.Cmp(want) != 0 {
+		t.Errorf("position %s, want %s", no, want)
+	}
+}
@@ -2,6 +2,7 @@ package polymarket
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/google/uuid"
 )
@@ -351,7 +353,7 @@ func (c *Client) EnableTrading(wallet *web3.Wallet, signature, proxyAddress, pol
 
 	payload := PayloadEnableTrading{
 		From:        wallet.Address.String(),
-		To:          "0xaacFeEa03eb1561C4e67d661e40682Bd20E3541b",
+		To:          wallet.Network.SafeFactory.Hex(),
 		ProxyWallet: proxyAddress,
 		Data:        "0x",
 		Signature:   signature,
@@ -405,66 +407,80 @@ func (c *Client) EnableTrading(wallet *web3.Wallet, signature, proxyAddress, pol
 
 	if resp.StatusCode == 200 {
 		fmt.Println(data.TransactionID, data.State)
-		_ = c.CheckTxStatus(data.TransactionID, polyNonce, polySession)
+		if _, err := c.CheckTxStatus(context.Background(), data.TransactionID, polyNonce, polySession); err != nil {
+			log.Print(err)
+			return
+		}
 		log.Print("success")
 	}
 }
 
-func (c *Client) CheckTxStatus(txID, polyNonce, polySession string) error {
-	for i := 0; i < 5; i++ {
-		const apiEndpoint = "https://relayer-v2.polymarket.com/transaction?id="
+// ErrTxPending means a relayer transaction was neither mined nor failed
+// when the context was done. It may still be mined, so it must not be
+// submitted again without checking it first.
+var ErrTxPending = errors.New("relayer tx still pending")
 
-		fmt.Println(apiEndpoint + txID)
+// RelayerTimeout bounds CheckTxStatus when its context has no deadline.
+const RelayerTimeout = 3 * time.Minute
 
-		req, _ := http.NewRequest(http.MethodGet, apiEndpoint+txID, nil)
+// relayerPoll is how often CheckTxStatus asks the relayer.
+var relayerPoll = 3 * time.Second
 
-		req.Header.Set("accept", "application/json, text/plain, */*")
-		req.Header.Set("accept-language", "ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7")
-		req.Header.Set("content-type", "application/json")
-		req.Header.Set("cookie", fmt.Sprintf("AMP_MKTG_4572e28e5c=JTdCJTIycmVmZXJyZXIlMjIlM0ElMjJodHRwcyUzQSUyRiUyRnd3dy5nb29nbGUuY29tJTJGJTIyJTJDJTIycmVmZXJyaW5nX2RvbWFpbiUyMiUzQSUyMnd3dy5nb29nbGUuY29tJTIyJTdE; polymarketnonce=%s; polymarketsession=%s; polymarketauthtype=metamask; AMP_4572e28e5c=JTdCJTdE", polyNonce, polySession))
-		req.Header.Set("origin", "https://polymarket.com")
-		req.Header.Set("priority", "u=1, i")
-		req.Header.Set("sec-ch-ua", `"Google Chrome";v="129", "Not=A?Brand";v="8", "Chromium";v="129"`)
-		req.Header.Set("sec-ch-ua-mobile", "?0")
-		req.Header.Set("sec-ch-ua-platform", `"Windows"`)
-		req.Header.Set("sec-fetch-dest", "empty")
-		req.Header.Set("sec-fetch-mode", "cors")
-		req.Header.Set("sec-fetch-site", "same-site")
-		req.Header.Set("user-agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36")
+// CheckTxStatus polls the relayer until transaction txID is mined or failed
+// and returns its on-chain hash. When ctx is done first it returns
+// ErrTxPending with the transaction id and its last known state.
+func (c *Client) CheckTxStatus(ctx context.Context, txID, polyNonce, polySession string) (common.Hash, error) {
+	const apiEndpoint = "https://relayer-v2.polymarket.com/transaction?id="
 
-		time.Sleep(2 * time.Second)
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, RelayerTimeout)
+		defer cancel()
+	}
 
-		resp, err := c.Client.Do(req)
-		if err != nil {
-			_ = fmt.Errorf("error when request to check tx %w", err)
+	ticker := time.NewTicker(relayerPoll)
+	defer ticker.Stop()
+
+	state := "unknown"
+	for {
+		select {
+		case <-ctx.Done():
+			return common.Hash{}, fmt.Errorf("%w: %s, last state %s", ErrTxPending, txID, state)
+		case <-ticker.C:
 		}
 
-		fmt.Println(resp.StatusCode)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, apiEndpoint+txID, nil)
+		setRelayerHeaders(req, polyNonce, polySession)
 
-		defer resp.Body.Close()
+		resp, err := c.Client.Do(req)
+		if err != nil {
+			log.Printf("error when request to check tx: %v", err)
+			continue
+		}
 
 		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
-			_ = fmt.Errorf("error when read body tx %w", err)
+			log.Printf("error when read body tx: %v", err)
+			continue
 		}
 
 		var data RespTxCheck
 
-		err = json.Unmarshal(body, &data)
-		if err != nil {
-			fmt.Println(err)
+		if resp.StatusCode != http.StatusOK || json.Unmarshal(body, &data) != nil || len(data) == 0 {
+			log.Printf("Tx status unavailable %d", resp.StatusCode)
+			continue
 		}
 
-		if resp.StatusCode == 200 {
-			if data[0].State == "STATE_MINED" {
-				log.Printf("Tx is mined %s", data[0].State)
-				break
-			} else {
-				log.Printf("Tx is not mined %s", data[0].State)
-				time.Sleep(5 * time.Second)
-			}
+		switch data[0].State {
+		case "STATE_MINED", "STATE_CONFIRMED":
+			log.Printf("Tx is mined %s", data[0].TransactionHash)
+			return common.HexToHash(data[0].TransactionHash), nil
+		case "STATE_FAILED", "STATE_INVALID":
+			return common.Hash{}, fmt.Errorf("relayer tx %s failed: %s", txID, data[0].State)
+		default:
+			state = data[0].State
+			log.Printf("Tx is not mined %s", state)
 		}
 	}
-
-	return errors.New("tx check failed")
 }
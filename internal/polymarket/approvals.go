@@ -0,0 +1,44 @@
+package polymarket
+
+import (
+	"context"
+	"polymarket/internal/web3"
+
+	"github.com/charmbracelet/log"
+)
+
+// SetupApprovals grants the trading approvals the wallet's Safe proxy is
+// missing in one relayed batch and reports the state of each. Running it
+// again once everything is approved submits nothing.
+func SetupApprovals(pc *Client, wc *web3.Wallet, session *Session) ([]web3.ApprovalStatus, error) {
+	safe, err := wc.Safe()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := safe.Approvals(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	calls, err := safe.ApprovalCalls(statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(calls) > 0 {
+		log.Printf("Granting %d approvals | %s", len(calls), safe.Address)
+
+		if _, err := pc.ExecSafeBatch(context.Background(), wc, calls, session.PolyNonce, session.PolySession); err != nil {
+			return statuses, err
+		}
+
+		statuses = web3.MarkGranted(statuses)
+	}
+
+	for _, status := range statuses {
+		log.Printf("%s | %s", safe.Address, status)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,139 @@
+package polymarket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"polymarket/internal/web3"
+	"polymarket/utils/client"
+)
+
+// ClobClient talks to the Polymarket CLOB (order book) API for one wallet.
+// Private endpoints use the wallet's API credentials from Creds, created on
+// first use with KeyNonce.
+type ClobClient struct {
+	Client   *http.Client
+	Host     string
+	Wallet   *web3.Wallet
+	Creds    CredentialStore
+	KeyNonce int64
+}
+
+func NewClob(wallet *web3.Wallet, creds CredentialStore) *ClobClient {
+	if creds == nil {
+		creds = NewMemoryStore()
+	}
+
+	return &ClobClient{
+		Client: client.New(),
+		Host:   clobURL,
+		Wallet: wallet,
+		Creds:  creds,
+	}
+}
+
+// APIError is a non-2xx answer of the CLOB.
+type APIError struct {
+	Method  string
+	Path    string
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("clob %s %s: status %d: %s", e.Method, e.Path, e.Status, e.Message)
+}
+
+// do sends a request with headers and decodes a JSON answer into out, if
+// out is not nil.
+func (c *ClobClient) do(method, path string, body any, headers http.Header, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error when marshal %s body: %w", path, err)
+		}
+	}
+
+	return c.doRaw(method, path, payload, headers, out)
+}
+
+// doL2 sends a request to a private endpoint, signed with the wallet's API
+// credentials.
+func (c *ClobClient) doL2(method, path string, body any, out any) error {
+	creds, err := c.ApiCreds()
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	if body != nil {
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error when marshal %s body: %w", path, err)
+		}
+	}
+
+	headers, err := c.l2Headers(creds, method, path, payload)
+	if err != nil {
+		return err
+	}
+
+	return c.doRaw(method, path, payload, headers, out)
+}
+
+func (c *ClobClient) doRaw(method, path string, payload []byte, headers http.Header, out any) error {
+	req, err := http.NewRequest(method, c.Host+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("can't build request %s %s: %w", method, path, err)
+	}
+
+	req.Header.Set("accept", "application/json")
+	if payload != nil {
+		req.Header.Set("content-type", "application/json")
+	}
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error when request %s %s: %w", method, path, err)
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error when read body %s %s: %w", method, path, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &APIError{Method: method, Path: path, Status: resp.StatusCode, Message: errorMessage(respBody)}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("error when unmarshal %s %s: %w", method, path, err)
+	}
+
+	return nil
+}
+
+// errorMessage pulls the message out of a CLOB error body, {"error": "..."}.
+func errorMessage(body []byte) string {
+	var data struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &data) == nil && data.Error != "" {
+		return data.Error
+	}
+
+	return string(body)
+}
@@ -0,0 +1,276 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"polymarket/internal/web3"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestHMACSignature(t *testing.T) {
+	// The vector of py-clob-client's and clob-client's signing tests. The
+	// body is a dict as py-clob-client serializes it, str(body) with single
+	// quotes replaced, hence the space after the colon.
+	const (
+		secret    = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+		timestamp = "1000000"
+		method    = "test-sign"
+		path      = "/orders"
+		body      = `{"hash": "0x123"}`
+		want      = "ZwAdJKvoYRlEKDkNMwd5BuwNNtg93kNaR_oU2HrfVvc="
+	)
+
+	got, err := HMACSignature(secret, timestamp, method, path, []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("signature %s, want %s", got, want)
+	}
+
+	// The body is signed byte for byte: the compact encoding Go sends is a
+	// different message.
+	compact, err := HMACSignature(secret, timestamp, method, path, []byte(`{"hash":"0x123"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compact == want {
+		t.Error("compact body has the same signature")
+	}
+
+	if _, err := HMACSignature("not base64!", timestamp, method, path, nil); err == nil {
+		t.Error("invalid secret was accepted")
+	}
+}
+
+// TestL2RequestSignsSentBody checks on the receiving side that the
+// signature covers exactly the method, path and body that went out, and
+// not the query string.
+func TestL2RequestSignsSentBody(t *testing.T) {
+	creds := &ApiCreds{Key: "key", Secret: "c2VjcmV0LXNlY3JldC1zZWNyZXQtc2VjcmV0IQ==", Passphrase: "pass"}
+	address := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		want, err := HMACSignature(creds.Secret, r.Header.Get("POLY_TIMESTAMP"), r.Method, r.URL.Path, body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		switch {
+		case r.Header.Get("POLY_SIGNATURE") != want:
+			t.Errorf("%s %s: signature %s, want %s over %q", r.Method, r.URL, r.Header.Get("POLY_SIGNATURE"), want, body)
+		case r.Header.Get("POLY_API_KEY") != creds.Key || r.Header.Get("POLY_PASSPHRASE") != creds.Passphrase:
+			t.Errorf("%s %s: wrong credential headers", r.Method, r.URL)
+		case r.Header.Get("POLY_ADDRESS") != address.Hex():
+			t.Errorf("%s %s: address %s", r.Method, r.URL, r.Header.Get("POLY_ADDRESS"))
+		}
+
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	store := NewMemoryStore()
+	if err := store.Save(address, creds); err != nil {
+		t.Fatal(err)
+	}
+
+	clob := NewClob(&web3.Wallet{Address: address}, store)
+	clob.Host = server.URL
+
+	requests := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodPost, "/order", map[string]any{"order": map[string]string{"side": "BUY", "note": `"quoted" <html> & ünïcode`}, "owner": "key"}},
+		{http.MethodDelete, "/orders", []string{"0x1", "0x2"}},
+		{http.MethodGet, "/data/orders?market=0xabc&next_cursor=MA==", nil},
+	}
+
+	for _, r := range requests {
+		if err := clob.doL2(r.method, r.path, r.body, nil); err != nil {
+			t.Errorf("%s %s: %v", r.method, r.path, err)
+		}
+	}
+}
+
+// TestClobAuthSignature signs the ClobAuth message of py-clob-client's
+// signing tests and compares with their signature.
+func TestClobAuthSignature(t *testing.T) {
+	clob := amoyClob(t)
+
+	signature, err := clob.clobAuthSignature("10000000", 23)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0xf62319a987514da40e57e2f4d7529f7bac38f0355bd88bb5adbb3768d80de6c1682518e0af677d5260366425f4361e7b70c25ae232aff0ab2331e2b164a1aedc1b"; signature != want {
+		t.Errorf("signature %s, want %s", signature, want)
+	}
+}
+
+// checkL1Headers checks that r carries a ClobAuth signature of address for
+// nonce on Polygon.
+func checkL1Headers(t *testing.T, r *http.Request, address common.Address, nonce int64) {
+	t.Helper()
+
+	if r.Header.Get("POLY_ADDRESS") != address.Hex() || r.Header.Get("POLY_NONCE") != strconv.FormatInt(nonce, 10) {
+		t.Errorf("%s %s: address %s nonce %s", r.Method, r.URL, r.Header.Get("POLY_ADDRESS"), r.Header.Get("POLY_NONCE"))
+		return
+	}
+
+	data, err := web3.NewTypedData(ClobAuthDomain(web3.Polygon), "ClobAuth", ClobAuth{
+		Address:   address,
+		Timestamp: r.Header.Get("POLY_TIMESTAMP"),
+		Nonce:     big.NewInt(nonce),
+		Message:   clobAuthMessage,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	signature, err := hexutil.Decode(r.Header.Get("POLY_SIGNATURE"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := web3.VerifyTypedData(address, data, signature); err != nil {
+		t.Errorf("%s %s: %v", r.Method, r.URL, err)
+	}
+}
+
+func TestCreateOrDeriveApiKey(t *testing.T) {
+	const created = `{"apiKey":"created","secret":"c2VjcmV0","passphrase":"p"}`
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		derive  bool
+		wantKey string
+	}{
+		{"created", http.StatusOK, created, false, "created"},
+		{"exists", http.StatusBadRequest, `{"error":"Could not create api key"}`, true, "derived"},
+		{"answered without key", http.StatusOK, `{}`, true, "derived"},
+		{"bad signature", http.StatusUnauthorized, `{"error":"Unauthorized/Invalid api key"}`, false, ""},
+		{"other bad request", http.StatusBadRequest, `{"error":"Invalid L1 Request headers"}`, false, ""},
+		{"server error", http.StatusInternalServerError, `{"error":"internal"}`, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			derived := false
+
+			var clob *ClobClient
+			clob = newTestClob(t, func(w http.ResponseWriter, r *http.Request) {
+				checkL1Headers(t, r, clob.Wallet.Address, 5)
+
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/auth/api-key":
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.body))
+				case r.Method == http.MethodGet && r.URL.Path == "/auth/derive-api-key":
+					derived = true
+					w.Write([]byte(`{"apiKey":"derived","secret":"c2VjcmV0","passphrase":"p"}`))
+				default:
+					t.Errorf("request %s %s", r.Method, r.URL)
+				}
+			})
+
+			creds, err := clob.CreateOrDeriveApiKey(5)
+			if derived != tt.derive {
+				t.Errorf("derived %v, want %v", derived, tt.derive)
+			}
+			if tt.wantKey == "" {
+				if err == nil {
+					t.Fatalf("got key %s, want an error", creds.Key)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if creds.Key != tt.wantKey {
+				t.Errorf("key %s, want %s", creds.Key, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestApiKeyLifecycle(t *testing.T) {
+	newCreds := &ApiCreds{Key: "new", Secret: testCreds.Secret, Passphrase: "p"}
+
+	var (
+		clob    *ClobClient
+		deleted bool
+	)
+	clob = newTestClob(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/auth/api-key":
+			checkL1Headers(t, r, clob.Wallet.Address, clob.KeyNonce)
+			json.NewEncoder(w).Encode(newCreds)
+		case r.Method == http.MethodGet && r.URL.Path == "/auth/api-keys":
+			if r.Header.Get("POLY_API_KEY") != newCreds.Key {
+				t.Errorf("listed with key %q", r.Header.Get("POLY_API_KEY"))
+			}
+			w.Write([]byte(`{"apiKeys":["new","old"]}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/auth/api-key":
+			if r.Header.Get("POLY_API_KEY") != newCreds.Key {
+				t.Errorf("deleted with key %q", r.Header.Get("POLY_API_KEY"))
+			}
+			deleted = true
+		default:
+			t.Errorf("request %s %s", r.Method, r.URL)
+		}
+	})
+	clob.KeyNonce = 9
+	if err := clob.Creds.Delete(clob.Wallet.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := clob.ApiCreds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *creds != *newCreds {
+		t.Fatalf("creds %+v, want the created ones", creds)
+	}
+	if stored, err := clob.Creds.Load(clob.Wallet.Address); err != nil || *stored != *newCreds {
+		t.Fatalf("stored %+v (%v)", stored, err)
+	}
+
+	keys, err := clob.ListApiKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"new", "old"}) {
+		t.Errorf("keys %v", keys)
+	}
+
+	if err := clob.DeleteApiKey(); err != nil {
+		t.Fatal(err)
+	}
+	if !deleted {
+		t.Error("key was not revoked")
+	}
+	if _, err := clob.Creds.Load(clob.Wallet.Address); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("revoked key is still stored: %v", err)
+	}
+}
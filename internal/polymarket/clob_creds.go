@@ -0,0 +1,192 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrNoCredentials is returned by a CredentialStore without credentials for
+// an address.
+var ErrNoCredentials = errors.New("no api credentials stored")
+
+// CredentialStore keeps the CLOB API credentials of wallets.
+type CredentialStore interface {
+	Load(address common.Address) (*ApiCreds, error)
+	Save(address common.Address, creds *ApiCreds) error
+	Delete(address common.Address) error
+}
+
+// MemoryStore keeps credentials for the life of the process.
+type MemoryStore struct {
+	mu    sync.Mutex
+	creds map[common.Address]ApiCreds
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{creds: make(map[common.Address]ApiCreds)}
+}
+
+func (s *MemoryStore) Load(address common.Address) (*ApiCreds, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	creds, ok := s.creds[address]
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	return &creds, nil
+}
+
+func (s *MemoryStore) Save(address common.Address, creds *ApiCreds) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.creds[address] = *creds
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(address common.Address) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.creds, address)
+
+	return nil
+}
+
+// FileStore keeps credentials as one file per address in Dir, encrypted
+// with Passphrase like a V3 keystore and readable by the owner only. Files
+// other users can access are refused. ScryptN and ScryptP default to the
+// keystore's standard parameters.
+type FileStore struct {
+	Dir        string
+	Passphrase string
+	ScryptN    int
+	ScryptP    int
+}
+
+// credsFile is the stored form of ApiCreds.
+type credsFile struct {
+	Address string              `json:"address"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+}
+
+func (s *FileStore) path(address common.Address) string {
+	return filepath.Join(s.Dir, strings.ToLower(address.Hex())+".json")
+}
+
+func (s *FileStore) Load(address common.Address) (*ApiCreds, error) {
+	path := s.path(address)
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read api credentials: %w", err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		return nil, fmt.Errorf("api credentials %s are accessible by other users (%s), chmod 600 it", path, perm)
+	}
+
+	if s.Passphrase == "" {
+		return nil, errors.New("no passphrase for api credentials")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read api credentials: %w", err)
+	}
+
+	var file credsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error when unmarshal api credentials %s: %w", path, err)
+	}
+	if file.Crypto.Cipher == "" {
+		return nil, fmt.Errorf("api credentials %s are not encrypted, delete the file and derive them again", path)
+	}
+
+	plain, err := keystore.DecryptDataV3(file.Crypto, s.Passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("can't decrypt api credentials %s: %w", path, err)
+	}
+
+	var creds ApiCreds
+	if err := json.Unmarshal(plain, &creds); err != nil {
+		return nil, fmt.Errorf("error when unmarshal api credentials %s: %w", path, err)
+	}
+
+	return &creds, nil
+}
+
+// Save encrypts creds and replaces the file of address atomically, so a
+// crash never leaves a partial file behind.
+func (s *FileStore) Save(address common.Address, creds *ApiCreds) error {
+	if s.Passphrase == "" {
+		return errors.New("no passphrase for api credentials")
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return fmt.Errorf("can't create credentials dir: %w", err)
+	}
+
+	plain, err := json.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("error when marshal api credentials: %w", err)
+	}
+
+	scryptN, scryptP := s.ScryptN, s.ScryptP
+	if scryptN == 0 || scryptP == 0 {
+		scryptN, scryptP = keystore.StandardScryptN, keystore.StandardScryptP
+	}
+
+	encrypted, err := keystore.EncryptDataV3(plain, []byte(s.Passphrase), scryptN, scryptP)
+	if err != nil {
+		return fmt.Errorf("can't encrypt api credentials: %w", err)
+	}
+
+	data, err := json.Marshal(credsFile{Address: address.Hex(), Crypto: encrypted})
+	if err != nil {
+		return fmt.Errorf("error when marshal api credentials: %w", err)
+	}
+
+	// CreateTemp makes the file with mode 0600.
+	tmp, err := os.CreateTemp(s.Dir, ".creds-*")
+	if err != nil {
+		return fmt.Errorf("can't write api credentials: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("can't write api credentials: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("can't write api credentials: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(address)); err != nil {
+		return fmt.Errorf("can't write api credentials: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) Delete(address common.Address) error {
+	err := os.Remove(s.path(address))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("can't delete api credentials: %w", err)
+	}
+
+	return nil
+}
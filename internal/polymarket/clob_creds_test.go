@@ -0,0 +1,99 @@
+package polymarket
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func newTestFileStore(t *testing.T, passphrase string) *FileStore {
+	return &FileStore{
+		Dir:        filepath.Join(t.TempDir(), "creds"),
+		Passphrase: passphrase,
+		ScryptN:    keystore.LightScryptN,
+		ScryptP:    keystore.LightScryptP,
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	address := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	creds := &ApiCreds{Key: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+
+	store := newTestFileStore(t, "correct horse")
+
+	if _, err := store.Load(address); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("empty store: %v, want ErrNoCredentials", err)
+	}
+
+	if err := store.Save(address, creds); err != nil {
+		t.Fatal(err)
+	}
+
+	path := store.path(address)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("credentials saved with mode %s, want 0600", perm)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{creds.Secret, creds.Passphrase, `"key"`} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("stored file contains %q in the clear", secret)
+		}
+	}
+
+	loaded, err := store.Load(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != *creds {
+		t.Errorf("loaded %+v, want %+v", loaded, creds)
+	}
+
+	wrong := *store
+	wrong.Passphrase = "wrong"
+	if _, err := wrong.Load(address); err == nil {
+		t.Error("credentials decrypted with a wrong passphrase")
+	}
+
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(address); err == nil {
+		t.Error("credentials readable by others were loaded")
+	}
+
+	// Saving again fixes the mode, the file is replaced.
+	if err := store.Save(address, creds); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(address); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Delete(address); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(address); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("deleted credentials: %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestFileStoreNeedsPassphrase(t *testing.T) {
+	store := newTestFileStore(t, "")
+
+	if err := store.Save(common.Address{}, &ApiCreds{Key: "key"}); err == nil {
+		t.Fatal("credentials saved without a passphrase")
+	}
+}
@@ -4,18 +4,26 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"polymarket/internal/web3"
 	"polymarket/utils"
 	"time"
 )
 
-func CreateAccount(pc *Client, wc *web3.Wallet, ampCookie string) error {
+// Session is a signed-in Polymarket browser session, needed by the relayer.
+type Session struct {
+	PolyNonce   string
+	PolySession string
+}
+
+// SignIn signs in with the wallet's key (Sign-In with Ethereum).
+func SignIn(pc *Client, wc *web3.Wallet, ampCookie string) (*Session, error) {
 	currTime := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
 	expTime := time.Now().UTC().Add(7 * 24 * time.Hour).Format("2006-01-02T15:04:05.000Z")
 
 	polyNonce, nonce, err := pc.GetNonce()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	msgToSign := fmt.Sprintf(`polymarket.com wants you to sign in with your Ethereum account:
@@ -32,20 +40,30 @@ Expiration Time: %s`, wc.Address, nonce, currTime, expTime)
 
 	signature, err := wc.SignMsg(msgToSign)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	token, err := createBearerToken(wc, nonce, currTime, expTime, signature)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	proxyAddress, err := wc.CreateProxyAddress()
+	polySession, err := pc.Login(polyNonce, token, ampCookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{PolyNonce: polyNonce, PolySession: polySession}, nil
+}
+
+func CreateAccount(pc *Client, wc *web3.Wallet, ampCookie string) error {
+	session, err := SignIn(pc, wc, ampCookie)
 	if err != nil {
 		return err
 	}
+	polyNonce, polySession := session.PolyNonce, session.PolySession
 
-	polySession, err := pc.Login(polyNonce, token, ampCookie)
+	proxyAddress, err := wc.CreateProxyAddress()
 	if err != nil {
 		return err
 	}
@@ -65,8 +83,10 @@ Expiration Time: %s`, wc.Address, nonce, currTime, expTime)
 
 	time.Sleep(5 * time.Second)
 
-	msgToSignTrade := MsgTradingEnable()
-	tradeOnSig, _ := wc.SignTypedMsg(msgToSignTrade)
+	tradeOnSig, err := signTyped(wc, ProxyFactoryDomain(wc.Network), "CreateProxy", CreateProxy{Payment: big.NewInt(0)})
+	if err != nil {
+		return err
+	}
 
 	pc.EnableTrading(wc, tradeOnSig, proxyAddress, polyNonce, polySession)
 
@@ -96,49 +116,3 @@ func createBearerToken(wc *web3.Wallet, nonce, currTime, expTime, signature stri
 
 	return data, nil
 }
-
-func MsgTradingEnable() string {
-	return `{
-		"types": {
-			"CreateProxy": [
-				{
-					"name": "paymentToken",
-					"type": "address"
-				},
-				{
-					"name": "payment",
-					"type": "uint256"
-				},
-				{
-					"name": "paymentReceiver",
-					"type": "address"
-				}
-			],
-			"EIP712Domain": [
-				{
-					"name": "name",
-					"type": "string"
-				},
-				{
-					"name": "chainId",
-					"type": "uint256"
-				},
-				{
-					"name": "verifyingContract",
-					"type": "address"
-				}
-			]
-		},
-		"primaryType": "CreateProxy",
-		"domain": {
-			"name": "Polymarket Contract Proxy Factory",
-			"chainId": "137",
-			"verifyingContract": "0xaacfeea03eb1561c4e67d661e40682bd20e3541b"
-		},
-		"message": {
-			"paymentToken": "0x0000000000000000000000000000000000000000",
-			"payment": "0",
-			"paymentReceiver": "0x0000000000000000000000000000000000000000"
-		}
-	}`
-}
@@ -0,0 +1,92 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"polymarket/internal/web3"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const dataAPIURL = "https://data-api.polymarket.com"
+
+// positionsPageLimit is the page size asked from /positions, the most the
+// data API returns at once.
+var positionsPageLimit = 500
+
+// GetPositions lists the outcome token positions the data API knows user
+// holds, reading every page. Sizes are indicative only; read balances on
+// chain before acting.
+func (c *Client) GetPositions(user string) ([]DataPosition, error) {
+	var positions []DataPosition
+
+	for offset := 0; ; offset += positionsPageLimit {
+		page, err := c.getPositionsPage(user, offset)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, page...)
+
+		if len(page) < positionsPageLimit {
+			return positions, nil
+		}
+	}
+}
+
+func (c *Client) getPositionsPage(user string, offset int) ([]DataPosition, error) {
+	query := url.Values{
+		"user":          {user},
+		"sizeThreshold": {"0"},
+		"limit":         {strconv.Itoa(positionsPageLimit)},
+		"offset":        {strconv.Itoa(offset)},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/positions?%s", dataAPIURL, query.Encode()), nil)
+	req.Header.Set("accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error when request positions: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error when read body positions: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("positions status %d: %s", resp.StatusCode, body)
+	}
+
+	var positions []DataPosition
+	if err := json.Unmarshal(body, &positions); err != nil {
+		return nil, fmt.Errorf("error when unmarshal positions: %w", err)
+	}
+
+	return positions, nil
+}
+
+// Position is the on-chain position the data API entry refers to.
+func (p DataPosition) Position() (web3.Position, error) {
+	id, ok := new(big.Int).SetString(p.Asset, 10)
+	if !ok {
+		return web3.Position{}, fmt.Errorf("invalid position id %q", p.Asset)
+	}
+	if p.OutcomeIndex < 0 {
+		return web3.Position{}, fmt.Errorf("invalid outcome index %d", p.OutcomeIndex)
+	}
+
+	return web3.Position{
+		ID:          id,
+		ConditionID: common.HexToHash(p.ConditionID),
+		IndexSet:    new(big.Int).Lsh(big.NewInt(1), uint(p.OutcomeIndex)),
+		NegRisk:     p.NegativeRisk,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package polymarket
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGetPositionsPages(t *testing.T) {
+	positionsPageLimit = 2
+	t.Cleanup(func() { positionsPageLimit = 500 })
+
+	const user = "0x6d8c4e9aDF5748Af82Dabe2C6225207770d6B4fa"
+	const total = 5
+
+	var offsets []int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/positions" || query.Get("user") != user || query.Get("sizeThreshold") != "0" || query.Get("limit") != "2" {
+			t.Errorf("unexpected request %s", r.URL)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		offset, err := strconv.Atoi(query.Get("offset"))
+		if err != nil {
+			t.Errorf("offset %q", query.Get("offset"))
+			http.Error(w, "bad offset", http.StatusBadRequest)
+			return
+		}
+		offsets = append(offsets, offset)
+
+		var page []string
+		for i := offset; i < total && i < offset+2; i++ {
+			page = append(page, fmt.Sprintf(`{"proxyWallet":%q,"asset":"%d","conditionId":"0x%064x","size":12.5,"redeemable":true,"outcomeIndex":%d,"negativeRisk":false}`,
+				user, 100+i, i/2, i%2))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(page, ","))
+	})
+
+	positions, err := client.GetPositions(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(positions) != total {
+		t.Fatalf("%d positions, want %d", len(positions), total)
+	}
+	for i, p := range positions {
+		if p.Asset != strconv.Itoa(100+i) {
+			t.Errorf("position %d is asset %s, want %d", i, p.Asset, 100+i)
+		}
+	}
+	if fmt.Sprint(offsets) != "[0 2 4]" {
+		t.Errorf("offsets %v, want [0 2 4]", offsets)
+	}
+}
+
+func TestGetPositionsError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"rate limited"}`, http.StatusTooManyRequests)
+	})
+
+	if _, err := client.GetPositions("0x6d8c4e9aDF5748Af82Dabe2C6225207770d6B4fa"); err == nil {
+		t.Fatal("positions read from an error")
+	}
+}
+
+func TestRedeemCandidates(t *testing.T) {
+	const condition = "0x00000000000000000000000000000000000000000000000000000000000000c1"
+
+	held := []DataPosition{
+		{Asset: "11", ConditionID: condition, OutcomeIndex: 0},
+		{Asset: "12", ConditionID: condition, OutcomeIndex: 1, NegativeRisk: true},
+		// Repeated by overlapping pages.
+		{Asset: "11", ConditionID: condition, OutcomeIndex: 0},
+	}
+
+	candidates, err := redeemCandidates(held)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("%d candidates, want 2", len(candidates))
+	}
+
+	yes, no := candidates[0], candidates[1]
+	if yes.ID.Int64() != 11 || yes.IndexSet.Int64() != 1 || yes.NegRisk || yes.ConditionID.Hex() != condition {
+		t.Errorf("first candidate %+v", yes)
+	}
+	if no.ID.Int64() != 12 || no.IndexSet.Int64() != 2 || !no.NegRisk {
+		t.Errorf("second candidate %+v", no)
+	}
+
+	for _, bad := range []DataPosition{
+		{Asset: "0x0b", ConditionID: condition},
+		{Asset: "11", ConditionID: condition, OutcomeIndex: -1},
+	} {
+		if _, err := redeemCandidates([]DataPosition{bad}); err == nil {
+			t.Errorf("candidate from %+v", bad)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxDecimalExponent bounds exponents like 1e-9, so a hostile response
+// can't make a Decimal of millions of digits.
+const maxDecimalExponent = 1000
+
+var decimalPattern = regexp.MustCompile(`^([+-]?)([0-9]*)(?:\.([0-9]*))?(?:[eE]([+-]?[0-9]+))?$`)
+
+// Decimal is an exact CLOB price or size. The API sends them as strings or
+// JSON numbers, with any number of decimals and possibly an exponent.
+// The zero value is 0.
+type Decimal struct {
+	// unscaled / 10^scale, without trailing fractional zeros.
+	unscaled *big.Int
+	scale    int
+}
+
+// ParseDecimal parses decimal strings like "0.5", "-12", ".25" or "1e-7"
+// without rounding.
+func ParseDecimal(s string) (Decimal, error) {
+	m := decimalPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil || m[2]+m[3] == "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	exp := 0
+	if m[4] != "" {
+		var err error
+		exp, err = strconv.Atoi(m[4])
+		if err != nil || exp > maxDecimalExponent || exp < -maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("decimal %q: exponent out of range ±%d", s, maxDecimalExponent)
+		}
+	}
+
+	unscaled, ok := new(big.Int).SetString(m[2]+m[3], 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if m[1] == "-" {
+		unscaled.Neg(unscaled)
+	}
+
+	scale := len(m[3]) - exp
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+
+	return normalize(unscaled, scale), nil
+}
+
+func normalize(unscaled *big.Int, scale int) Decimal {
+	ten := big.NewInt(10)
+	q, r := new(big.Int), new(big.Int)
+	for scale > 0 && unscaled.Sign() != 0 {
+		q.QuoRem(unscaled, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		unscaled.Set(q)
+		scale--
+	}
+	if unscaled.Sign() == 0 {
+		scale = 0
+	}
+
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// Rat is the exact value of d.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.unscaledInt(), pow10(d.scale))
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+func (d Decimal) IsZero() bool {
+	return d.unscaledInt().Sign() == 0
+}
+
+// String formats d in plain notation with trailing fractional zeros
+// removed, e.g. "0.01" or "150".
+func (d Decimal) String() string {
+	unscaled := d.unscaledInt()
+
+	digits := new(big.Int).Abs(unscaled).String()
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+
+	s := digits
+	if d.scale > 0 {
+		s = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if unscaled.Sign() < 0 {
+		s = "-" + s
+	}
+
+	return s
+}
+
+func (d Decimal) unscaledInt() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+
+	return d.unscaled
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	if s == "" || s == "null" {
+		*d = Decimal{}
+		return nil
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return fmt.Errorf("error when unmarshal decimal %s: %w", data, err)
+	}
+	*d = parsed
+
+	return nil
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
@@ -0,0 +1,88 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecimalUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{`"0.5"`, "0.5"},
+		{`0.5`, "0.5"},
+		{`"0.010"`, "0.01"},
+		{`0.0001`, "0.0001"},
+		{`"0.00000001"`, "0.00000001"},
+		{`0.123456789`, "0.123456789"},
+		{`1e-7`, "0.0000001"},
+		{`"2.5E-3"`, "0.0025"},
+		{`1.5e+2`, "150"},
+		{`"1E2"`, "100"},
+		{`12345678901234567890.123456789012345678`, "12345678901234567890.123456789012345678"},
+		{`"-0.25"`, "-0.25"},
+		{`-3`, "-3"},
+		{`"100"`, "100"},
+		{`".5"`, "0.5"},
+		{`"5."`, "5"},
+		{`0`, "0"},
+		{`"0.000"`, "0"},
+		{`"-0"`, "0"},
+		{`""`, "0"},
+		{`null`, "0"},
+	}
+
+	for _, tt := range tests {
+		var d Decimal
+		if err := json.Unmarshal([]byte(tt.json), &d); err != nil {
+			t.Errorf("%s: %v", tt.json, err)
+			continue
+		}
+		if d.String() != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.json, d, tt.want)
+		}
+	}
+}
+
+func TestDecimalUnmarshalJSONRejects(t *testing.T) {
+	for _, data := range []string{`"abc"`, `"1.2.3"`, `"e5"`, `"."`, `"1e"`, `"0x10"`, `"1,5"`, `"1e1001"`, `"1e-99999999999999999999"`, `true`} {
+		var d Decimal
+		if err := json.Unmarshal([]byte(data), &d); err == nil {
+			t.Errorf("%s: got %s, want error", data, d)
+		}
+	}
+}
+
+func TestDecimalInStruct(t *testing.T) {
+	var book struct {
+		Price Decimal `json:"price"`
+		Size  Decimal `json:"size"`
+	}
+	if err := json.Unmarshal([]byte(`{"price": "0.4512345678", "size": 1.25e3}`), &book); err != nil {
+		t.Fatal(err)
+	}
+	if book.Price.String() != "0.4512345678" || book.Size.String() != "1250" {
+		t.Errorf("got price %s size %s", book.Price, book.Size)
+	}
+
+	data, err := json.Marshal(book)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"price":"0.4512345678","size":"1250"}` {
+		t.Errorf("marshalled %s", data)
+	}
+}
+
+func TestDecimalCmp(t *testing.T) {
+	a, _ := ParseDecimal("0.1")
+	b, _ := ParseDecimal("1e-1")
+	c, _ := ParseDecimal("0.1000001")
+	if a.Cmp(b) != 0 || a.Cmp(c) >= 0 || c.Cmp(a) <= 0 {
+		t.Errorf("bad ordering of %s, %s, %s", a, b, c)
+	}
+	if !(Decimal{}).IsZero() || a.IsZero() {
+		t.Error("bad IsZero")
+	}
+}
@@ -0,0 +1,154 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"polymarket/internal/ctfid"
+	"polymarket/internal/web3"
+
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const clobURL = "https://clob.polymarket.com"
+
+// GetMarket loads the CLOB metadata of the market of conditionID, including
+// whether it is part of a neg-risk event.
+func (c *Client) GetMarket(conditionID common.Hash) (*Market, error) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/markets/%s", clobURL, conditionID.Hex()), nil)
+	req.Header.Set("accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error when request market: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error when read body market: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("market status %d: %s", resp.StatusCode, body)
+	}
+
+	var market Market
+	if err := json.Unmarshal(body, &market); err != nil {
+		return nil, fmt.Errorf("error when unmarshal market: %w", err)
+	}
+
+	if common.HexToHash(market.ConditionID) != conditionID {
+		return nil, fmt.Errorf("market %s returned for condition %s", market.ConditionID, conditionID)
+	}
+
+	return &market, nil
+}
+
+// Positions are the outcome tokens of the market, in CLOB token order.
+// Every token id must be the position of its outcome in the collateral the
+// neg-risk flag implies, so a wrong flag or a reordered token list is an
+// error rather than a split or redemption routed to the wrong contract.
+func (m *Market) Positions(network *web3.Network) ([]web3.Position, error) {
+	if len(m.Tokens) == 0 {
+		return nil, fmt.Errorf("market %s has no tokens", m.ConditionID)
+	}
+
+	conditionID := common.HexToHash(m.ConditionID)
+
+	collateral := network.USDC
+	if m.NegRisk {
+		collateral = network.NegRiskCollateral
+	}
+
+	positions := make([]web3.Position, len(m.Tokens))
+	for i, token := range m.Tokens {
+		id, ok := new(big.Int).SetString(token.TokenID, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid token id %q", token.TokenID)
+		}
+
+		want, err := ctfid.OutcomePositionID(collateral, conditionID, uint(i))
+		if err != nil {
+			return nil, err
+		}
+		if id.Cmp(want) != 0 {
+			return nil, fmt.Errorf("market %s: token %d (%s) is %s, the position is %s with neg risk %t", m.ConditionID, i, token.Outcome, id, want, m.NegRisk)
+		}
+
+		positions[i] = web3.Position{
+			ID:          id,
+			ConditionID: conditionID,
+			IndexSet:    new(big.Int).Lsh(big.NewInt(1), uint(i)),
+			NegRisk:     m.NegRisk,
+		}
+	}
+
+	return positions, nil
+}
+
+// Verify checks the market against the chain before anything is sent for
+// it: the token ids must match, see Positions, a neg-risk condition must be
+// the adapter's for a question of the market, and the condition must be
+// prepared with one outcome per token.
+func (m *Market) Verify(ctx context.Context, wallet *web3.Wallet) error {
+	if _, err := m.Positions(wallet.Network); err != nil {
+		return err
+	}
+
+	conditionID := common.HexToHash(m.ConditionID)
+
+	if m.NegRisk {
+		questionID := common.HexToHash(m.QuestionID)
+		index := questionID[common.HashLength-1]
+
+		if web3.NegRiskQuestionID(common.HexToHash(m.NegRiskMarketID), index) != questionID {
+			return fmt.Errorf("question %s is not part of neg-risk market %s", m.QuestionID, m.NegRiskMarketID)
+		}
+		if wallet.Network.NegRiskConditionID(common.HexToHash(m.NegRiskMarketID), index) != conditionID {
+			return fmt.Errorf("condition %s is not the neg-risk adapter's for question %s", m.ConditionID, m.QuestionID)
+		}
+	}
+
+	slots, err := wallet.CTF().OutcomeSlotCount(ctx, conditionID)
+	if err != nil {
+		return err
+	}
+	if slots != uint64(len(m.Tokens)) {
+		return fmt.Errorf("condition %s has %d outcomes on chain, the market lists %d tokens", m.ConditionID, slots, len(m.Tokens))
+	}
+
+	return nil
+}
+
+// Exchange is the exchange contract the market's orders are signed for.
+func (m *Market) Exchange(network *web3.Network) common.Address {
+	return network.Exchange(m.NegRisk)
+}
+
+// ConvertPositions converts amount of NO of every question in indexSet of
+// the neg-risk market marketID through the relayer.
+func ConvertPositions(pc *Client, wc *web3.Wallet, session *Session, marketID common.Hash, indexSet *big.Int, amount web3.Amount) error {
+	safe, err := wc.Safe()
+	if err != nil {
+		return err
+	}
+
+	calls, err := safe.ConvertCalls(context.Background(), marketID, indexSet, amount)
+	if err != nil {
+		return err
+	}
+
+	if _, err := pc.ExecSafeBatch(context.Background(), wc, calls, session.PolyNonce, session.PolySession); err != nil {
+		return err
+	}
+
+	log.Printf("Converted %s NO | market %s | questions %b | %s", amount, marketID, indexSet, safe.Address)
+
+	return nil
+}
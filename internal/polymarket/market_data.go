@@ -0,0 +1,222 @@
+package polymarket
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// PriceLevel is one price of an order book side.
+type PriceLevel struct {
+	Price Decimal `json:"price"`
+	Size  Decimal `json:"size"`
+}
+
+// OrderBook is the book of one token. Bids and asks are ordered as the
+// CLOB sends them, best price last.
+type OrderBook struct {
+	Market       string       `json:"market"`
+	AssetID      string       `json:"asset_id"`
+	Hash         string       `json:"hash"`
+	Timestamp    string       `json:"timestamp"`
+	Bids         []PriceLevel `json:"bids"`
+	Asks         []PriceLevel `json:"asks"`
+	MinOrderSize Decimal      `json:"min_order_size"`
+	TickSize     Decimal      `json:"tick_size"`
+	NegRisk      bool         `json:"neg_risk"`
+}
+
+// LastTrade is the price and taker side of a token's last trade.
+type LastTrade struct {
+	TokenID string  `json:"token_id"`
+	Price   Decimal `json:"price"`
+	Side    Side    `json:"side"`
+}
+
+// BookParams selects a token, and for prices the side, of a batch request.
+type BookParams struct {
+	TokenID string `json:"token_id"`
+	Side    Side   `json:"side,omitempty"`
+}
+
+func tokenQuery(path, tokenID string, extra ...string) string {
+	query := url.Values{"token_id": {tokenID}}
+	for i := 0; i+1 < len(extra); i += 2 {
+		query.Set(extra[i], extra[i+1])
+	}
+
+	return path + "?" + query.Encode()
+}
+
+func tokenParams(tokenIDs []string) []BookParams {
+	params := make([]BookParams, len(tokenIDs))
+	for i, tokenID := range tokenIDs {
+		params[i] = BookParams{TokenID: tokenID}
+	}
+
+	return params
+}
+
+func (c *ClobClient) GetOrderBook(tokenID string) (*OrderBook, error) {
+	var book OrderBook
+	if err := c.do(http.MethodGet, tokenQuery("/book", tokenID), nil, nil, &book); err != nil {
+		return nil, err
+	}
+
+	return &book, nil
+}
+
+func (c *ClobClient) GetOrderBooks(tokenIDs []string) ([]OrderBook, error) {
+	var books []OrderBook
+	if err := c.do(http.MethodPost, "/books", tokenParams(tokenIDs), nil, &books); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// GetPrice is the best price a taker on side gets for tokenID.
+func (c *ClobClient) GetPrice(tokenID string, side Side) (Decimal, error) {
+	var data struct {
+		Price Decimal `json:"price"`
+	}
+	if err := c.do(http.MethodGet, tokenQuery("/price", tokenID, "side", string(side)), nil, nil, &data); err != nil {
+		return Decimal{}, err
+	}
+
+	return data.Price, nil
+}
+
+// GetPrices maps token id and side to best price.
+func (c *ClobClient) GetPrices(params []BookParams) (map[string]map[Side]Decimal, error) {
+	for _, p := range params {
+		if p.Side != Buy && p.Side != Sell {
+			return nil, fmt.Errorf("token %s: invalid side %q", p.TokenID, p.Side)
+		}
+	}
+
+	var prices map[string]map[Side]Decimal
+	if err := c.do(http.MethodPost, "/prices", params, nil, &prices); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+func (c *ClobClient) GetMidpoint(tokenID string) (Decimal, error) {
+	var data struct {
+		Mid Decimal `json:"mid"`
+	}
+	if err := c.do(http.MethodGet, tokenQuery("/midpoint", tokenID), nil, nil, &data); err != nil {
+		return Decimal{}, err
+	}
+
+	return data.Mid, nil
+}
+
+// GetMidpoints maps token id to midpoint.
+func (c *ClobClient) GetMidpoints(tokenIDs []string) (map[string]Decimal, error) {
+	var mids map[string]Decimal
+	if err := c.do(http.MethodPost, "/midpoints", tokenParams(tokenIDs), nil, &mids); err != nil {
+		return nil, err
+	}
+
+	return mids, nil
+}
+
+func (c *ClobClient) GetSpread(tokenID string) (Decimal, error) {
+	var data struct {
+		Spread Decimal `json:"spread"`
+	}
+	if err := c.do(http.MethodGet, tokenQuery("/spread", tokenID), nil, nil, &data); err != nil {
+		return Decimal{}, err
+	}
+
+	return data.Spread, nil
+}
+
+// GetSpreads maps token id to spread.
+func (c *ClobClient) GetSpreads(tokenIDs []string) (map[string]Decimal, error) {
+	var spreads map[string]Decimal
+	if err := c.do(http.MethodPost, "/spreads", tokenParams(tokenIDs), nil, &spreads); err != nil {
+		return nil, err
+	}
+
+	return spreads, nil
+}
+
+func (c *ClobClient) GetLastTradePrice(tokenID string) (*LastTrade, error) {
+	var trade LastTrade
+	if err := c.do(http.MethodGet, tokenQuery("/last-trade-price", tokenID), nil, nil, &trade); err != nil {
+		return nil, err
+	}
+	trade.TokenID = tokenID
+
+	return &trade, nil
+}
+
+func (c *ClobClient) GetLastTradePrices(tokenIDs []string) ([]LastTrade, error) {
+	var trades []LastTrade
+	if err := c.do(http.MethodPost, "/last-trades-prices", tokenParams(tokenIDs), nil, &trades); err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
+
+func (c *ClobClient) GetTickSize(tokenID string) (TickSize, error) {
+	var data struct {
+		MinimumTickSize Decimal `json:"minimum_tick_size"`
+	}
+	if err := c.do(http.MethodGet, tokenQuery("/tick-size", tokenID), nil, nil, &data); err != nil {
+		return "", err
+	}
+
+	tick := TickSize(data.MinimumTickSize.String())
+	switch tick {
+	case Tick01, Tick001, Tick0001, Tick00001:
+		return tick, nil
+	default:
+		return "", fmt.Errorf("token %s: unknown tick size %s", tokenID, tick)
+	}
+}
+
+// GetNegRisk tells whether tokenID belongs to a neg-risk market.
+func (c *ClobClient) GetNegRisk(tokenID string) (bool, error) {
+	var data struct {
+		NegRisk bool `json:"neg_risk"`
+	}
+	if err := c.do(http.MethodGet, tokenQuery("/neg-risk", tokenID), nil, nil, &data); err != nil {
+		return false, err
+	}
+
+	return data.NegRisk, nil
+}
+
+// GetFeeRateBps is the fee rate orders of tokenID must be signed with.
+func (c *ClobClient) GetFeeRateBps(tokenID string) (int64, error) {
+	var data struct {
+		BaseFee int64 `json:"base_fee"`
+	}
+	if err := c.do(http.MethodGet, tokenQuery("/fee-rate", tokenID), nil, nil, &data); err != nil {
+		return 0, err
+	}
+
+	return data.BaseFee, nil
+}
+
+// OrderOptions loads the tick size and neg-risk flag of tokenID to build
+// an order for it.
+func (c *ClobClient) OrderOptions(tokenID string, sigType SignatureType) (OrderOptions, error) {
+	tick, err := c.GetTickSize(tokenID)
+	if err != nil {
+		return OrderOptions{}, err
+	}
+
+	negRisk, err := c.GetNegRisk(tokenID)
+	if err != nil {
+		return OrderOptions{}, err
+	}
+
+	return OrderOptions{TickSize: tick, NegRisk: negRisk, SignatureType: sigType}, nil
+}
@@ -0,0 +1,161 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+const (
+	testYesToken = "71321045679252212594626385532706912750332728571942532289631379312455583992563"
+	testNoToken  = "52114319501245915516055106046884209969926127482827954674443846427813813222426"
+)
+
+// serveJSON answers requests of method to path with body and records the
+// request body in got.
+func serveJSON(t *testing.T, method, path, body string, got *[]BookParams) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method || r.URL.Path != path {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+
+		if got != nil {
+			payload, err := io.ReadAll(r.Body)
+			if err == nil {
+				err = json.Unmarshal(payload, got)
+			}
+			if err != nil {
+				t.Errorf("request body: %v", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}
+}
+
+func mustDecimal(t *testing.T, s string) Decimal {
+	t.Helper()
+
+	d, err := ParseDecimal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return d
+}
+
+func TestGetLastTradePrices(t *testing.T) {
+	var params []BookParams
+	clob := newTestClob(t, serveJSON(t, http.MethodPost, "/last-trades-prices",
+		`[{"token_id":"`+testYesToken+`","price":"0.56","side":"BUY"},{"token_id":"`+testNoToken+`","price":"0.44","side":"SELL"}]`,
+		&params))
+
+	trades, err := clob.GetLastTradePrices([]string{testYesToken, testNoToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(params) != 2 || params[0].TokenID != testYesToken || params[1].TokenID != testNoToken || params[0].Side != "" {
+		t.Errorf("requested %+v", params)
+	}
+
+	want := []struct {
+		tokenID string
+		price   string
+		side    Side
+	}{
+		{testYesToken, "0.56", Buy},
+		{testNoToken, "0.44", Sell},
+	}
+	if len(trades) != len(want) {
+		t.Fatalf("%d trades, want %d", len(trades), len(want))
+	}
+	for i, w := range want {
+		got := trades[i]
+		if got.TokenID != w.tokenID || got.Price.Cmp(mustDecimal(t, w.price)) != 0 || got.Side != w.side {
+			t.Errorf("trade %d: %s at %s %s, want %s at %s %s", i, got.TokenID, got.Price, got.Side, w.tokenID, w.price, w.side)
+		}
+	}
+}
+
+func TestGetPrices(t *testing.T) {
+	var params []BookParams
+	clob := newTestClob(t, serveJSON(t, http.MethodPost, "/prices",
+		`{"`+testYesToken+`":{"BUY":"0.55","SELL":"0.57"},"`+testNoToken+`":{"BUY":"0.43"}}`,
+		&params))
+
+	prices, err := clob.GetPrices([]BookParams{
+		{TokenID: testYesToken, Side: Buy},
+		{TokenID: testYesToken, Side: Sell},
+		{TokenID: testNoToken, Side: Buy},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(params) != 3 || params[1].TokenID != testYesToken || params[1].Side != Sell {
+		t.Errorf("requested %+v", params)
+	}
+
+	for _, w := range []struct {
+		tokenID string
+		side    Side
+		price   string
+	}{
+		{testYesToken, Buy, "0.55"},
+		{testYesToken, Sell, "0.57"},
+		{testNoToken, Buy, "0.43"},
+	} {
+		price, ok := prices[w.tokenID][w.side]
+		if !ok || price.Cmp(mustDecimal(t, w.price)) != 0 {
+			t.Errorf("%s %s price %s (%v), want %s", w.tokenID, w.side, price, ok, w.price)
+		}
+	}
+	if _, ok := prices[testNoToken][Sell]; ok {
+		t.Error("price for a side the CLOB did not send")
+	}
+
+	// A missing side is refused before anything is sent.
+	if _, err := clob.GetPrices([]BookParams{{TokenID: testYesToken}}); err == nil {
+		t.Error("prices requested without a side")
+	}
+}
+
+func TestGetTickSize(t *testing.T) {
+	tests := []struct {
+		body string
+		want TickSize
+	}{
+		{`{"minimum_tick_size":0.01}`, Tick001},
+		{`{"minimum_tick_size":0.001}`, Tick0001},
+		{`{"minimum_tick_size":"0.1"}`, Tick01},
+		{`{"minimum_tick_size":0.0001}`, Tick00001},
+		// Not a tick orders can be built for.
+		{`{"minimum_tick_size":0.05}`, ""},
+		{`{}`, ""},
+	}
+
+	for _, tt := range tests {
+		clob := newTestClob(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/tick-size" || r.URL.Query().Get("token_id") != testYesToken {
+				t.Errorf("unexpected request %s", r.URL)
+			}
+			io.WriteString(w, tt.body)
+		})
+
+		tick, err := clob.GetTickSize(testYesToken)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s: tick size %s accepted", tt.body, tick)
+			}
+			continue
+		}
+		if err != nil || tick != tt.want {
+			t.Errorf("%s: tick size %s (%v), want %s", tt.body, tick, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"polymarket/internal/ctfid"
+	"polymarket/internal/web3"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// trumpMarket is the ids the CLOB publishes for the Donald Trump question
+// of the neg-risk event "Presidential Election Winner 2024".
+const trumpMarket = `{
+	"condition_id": "0xdd22472e552920b8438158ea7238bfadfa4f736aa4cee91a6b86c39ead110917",
+	"question_id": "0xe3b1bc389210504ebcb9cffe4b0ed06ccac50561e0f24abb6379984cec030f00",
+	"question": "Will Donald Trump win the 2024 US Presidential Election?",
+	"neg_risk": true,
+	"neg_risk_market_id": "0xe3b1bc389210504ebcb9cffe4b0ed06ccac50561e0f24abb6379984cec030f00",
+	"tokens": [
+		{"token_id": "21742633143463906290569050155826241533067272736897614950488156847949938836455", "outcome": "Yes"},
+		{"token_id": "48331043336612883890938759509493159234755048973500640148014422747788308965732", "outcome": "No"}
+	]
+}`
+
+func recordedMarket(t *testing.T) *Market {
+	t.Helper()
+
+	var market Market
+	if err := json.Unmarshal([]byte(trumpMarket), &market); err != nil {
+		t.Fatal(err)
+	}
+
+	return &market
+}
+
+// slotsBackend answers every contract call with an outcome slot count.
+type slotsBackend struct {
+	web3.Backend
+	slots int64
+}
+
+func (b slotsBackend) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return common.BigToHash(big.NewInt(b.slots)).Bytes(), nil
+}
+
+// testMarket is a market whose tokens are the positions of its condition
+// in collateral, as derived by ctfid. Only the recorded market checks the
+// derivation itself.
+func testMarket(t *testing.T, conditionID common.Hash, collateral common.Address, negRisk bool) *Market {
+	t.Helper()
+
+	market := &Market{ConditionID: conditionID.Hex(), NegRisk: negRisk}
+	for i, outcome := range []string{"Yes", "No"} {
+		id, err := ctfid.OutcomePositionID(collateral, conditionID, uint(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		market.Tokens = append(market.Tokens, MarketToken{TokenID: id.String(), Outcome: outcome})
+	}
+
+	return market
+}
+
+func TestMarketPositions(t *testing.T) {
+	network := web3.Polygon
+	conditionID := crypto.Keccak256Hash([]byte("condition"))
+
+	tests := []struct {
+		name    string
+		market  func() *Market
+		wantErr bool
+	}{
+		{"recorded neg risk", func() *Market { return recordedMarket(t) }, false},
+		{"recorded neg risk flag missing", func() *Market {
+			market := recordedMarket(t)
+			market.NegRisk = false
+			return market
+		}, true},
+		{"recorded tokens swapped", func() *Market {
+			market := recordedMarket(t)
+			market.Tokens[0], market.Tokens[1] = market.Tokens[1], market.Tokens[0]
+			return market
+		}, true},
+		{"binary", func() *Market { return testMarket(t, conditionID, network.USDC, false) }, false},
+		{"neg risk", func() *Market { return testMarket(t, conditionID, network.NegRiskCollateral, true) }, false},
+		{"neg risk flag missing", func() *Market {
+			market := testMarket(t, conditionID, network.NegRiskCollateral, true)
+			market.NegRisk = false
+			return market
+		}, true},
+		{"neg risk flag wrong", func() *Market {
+			market := testMarket(t, conditionID, network.USDC, false)
+			market.NegRisk = true
+			return market
+		}, true},
+		{"tokens swapped", func() *Market {
+			market := testMarket(t, conditionID, network.USDC, false)
+			market.Tokens[0], market.Tokens[1] = market.Tokens[1], market.Tokens[0]
+			return market
+		}, true},
+		{"no tokens", func() *Market { return &Market{ConditionID: conditionID.Hex()} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions, err := tt.market().Positions(network)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err %v, want error %t", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			market := tt.market()
+			for i, position := range positions {
+				if position.IndexSet.Int64() != 1<<i || position.ConditionID != common.HexToHash(market.ConditionID) {
+					t.Errorf("position %d: index set %s condition %s", i, position.IndexSet, position.ConditionID)
+				}
+			}
+		})
+	}
+}
+
+func TestMarketVerifyNegRiskCondition(t *testing.T) {
+	network := web3.Polygon
+	wallet := &web3.Wallet{Network: network}
+
+	marketID := common.HexToHash("0xabcdef00")
+	questionID := web3.NegRiskQuestionID(marketID, 3)
+	otherMarket := common.HexToHash("0x12345600")
+
+	tests := []struct {
+		name        string
+		marketID    common.Hash
+		conditionID common.Hash
+	}{
+		{"question of another market", otherMarket, network.NegRiskConditionID(marketID, 3)},
+		{"condition of another question", marketID, network.NegRiskConditionID(marketID, 4)},
+		{"condition not prepared by the adapter", marketID, ctfid.ConditionID(common.HexToAddress("0x01"), questionID, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			market := testMarket(t, tt.conditionID, network.NegRiskCollateral, true)
+			market.QuestionID = questionID.Hex()
+			market.NegRiskMarketID = tt.marketID.Hex()
+
+			if err := market.Verify(context.Background(), wallet); err == nil {
+				t.Fatal("market was verified")
+			}
+		})
+	}
+}
+
+func TestMarketVerifyRecorded(t *testing.T) {
+	tests := []struct {
+		name    string
+		slots   int64
+		wantErr bool
+	}{
+		{"prepared", 2, false},
+		{"not prepared", 0, true},
+		{"more outcomes", 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := &web3.Wallet{Client: slotsBackend{slots: tt.slots}, Network: web3.Polygon}
+
+			err := recordedMarket(t).Verify(context.Background(), wallet)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
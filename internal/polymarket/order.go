@@ -0,0 +1,208 @@
+package polymarket
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"polymarket/internal/web3"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Side string
+
+const (
+	Buy  Side = "BUY"
+	Sell Side = "SELL"
+)
+
+// SignatureType tells the exchange how to check an order's signature and
+// which wallet the maker is.
+type SignatureType uint8
+
+const (
+	SignatureEOA            SignatureType = 0
+	SignaturePolyProxy      SignatureType = 1
+	SignaturePolyGnosisSafe SignatureType = 2
+)
+
+// TickSize is the minimum price increment of a market.
+type TickSize string
+
+const (
+	Tick01    TickSize = "0.1"
+	Tick001   TickSize = "0.01"
+	Tick0001  TickSize = "0.001"
+	Tick00001 TickSize = "0.0001"
+)
+
+// sizeDecimals is the precision of order sizes, in outcome tokens.
+const sizeDecimals = 2
+
+// OrderArgs is an order in human terms. Price and Size are decimal strings
+// so they are never rounded by float conversion.
+type OrderArgs struct {
+	TokenID    *big.Int
+	Side       Side
+	Price      string
+	Size       string
+	FeeRateBps int64
+	Nonce      int64
+	// Expiration is when a GTD order expires, zero for none.
+	Expiration time.Time
+	Taker      common.Address
+}
+
+// OrderOptions are the market properties and wallet setup an order is built
+// for.
+type OrderOptions struct {
+	TickSize      TickSize
+	NegRisk       bool
+	SignatureType SignatureType
+}
+
+type SignedOrder struct {
+	Order
+	Signature string
+	// Exchange the order is signed for.
+	Exchange common.Address
+}
+
+// OrderAmounts turns a price and size into exact maker and taker amounts in
+// base units. The price must be a multiple of the tick, the size is rounded
+// down to cents of a token; the product is then exact in 6 decimals.
+func OrderAmounts(side Side, price, size string, tick TickSize) (makerAmount, takerAmount *big.Int, err error) {
+	switch tick {
+	case Tick01, Tick001, Tick0001, Tick00001:
+	default:
+		return nil, nil, fmt.Errorf("invalid tick size %q", tick)
+	}
+
+	tickUnits, err := web3.ParseUSDC(string(tick))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid tick size %q", tick)
+	}
+
+	p, ok := new(big.Rat).SetString(price)
+	if !ok || p.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("invalid price %q", price)
+	}
+	s, ok := new(big.Rat).SetString(size)
+	if !ok || s.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("invalid size %q", size)
+	}
+
+	one := big.NewInt(1_000_000)
+
+	ticks := new(big.Rat).Quo(new(big.Rat).Mul(p, new(big.Rat).SetInt(one)), new(big.Rat).SetInt(tickUnits.Raw))
+	if !ticks.IsInt() {
+		return nil, nil, fmt.Errorf("price %s is not a multiple of tick size %s", price, tick)
+	}
+	priceUnits := new(big.Int).Mul(ticks.Num(), tickUnits.Raw)
+
+	if priceUnits.Cmp(tickUnits.Raw) < 0 || priceUnits.Cmp(new(big.Int).Sub(one, tickUnits.Raw)) > 0 {
+		return nil, nil, fmt.Errorf("price %s is outside [%s, 1-%s]", price, tick, tick)
+	}
+
+	cents := new(big.Rat).Mul(s, big.NewRat(100, 1))
+	sizeCents := new(big.Int).Quo(cents.Num(), cents.Denom())
+	if sizeCents.Sign() == 0 {
+		return nil, nil, fmt.Errorf("size %s is below the minimum of 0.%0*d", size, sizeDecimals, 1)
+	}
+
+	tokens := new(big.Int).Mul(sizeCents, big.NewInt(10_000))
+	usdc := new(big.Int).Mul(sizeCents, priceUnits)
+	usdc.Quo(usdc, big.NewInt(100))
+
+	switch side {
+	case Buy:
+		return usdc, tokens, nil
+	case Sell:
+		return tokens, usdc, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid side %q", side)
+	}
+}
+
+// maker is the wallet an order with sigType trades from.
+func (c *ClobClient) maker(sigType SignatureType) (common.Address, error) {
+	switch sigType {
+	case SignatureEOA:
+		return c.Wallet.Address, nil
+	case SignaturePolyProxy:
+		return c.Wallet.ProxyAddress(web3.ProxyPolymarket)
+	case SignaturePolyGnosisSafe:
+		proxyAddress, err := c.Wallet.CreateProxyAddress()
+		if err != nil {
+			return common.Address{}, err
+		}
+		return common.HexToAddress(proxyAddress), nil
+	default:
+		return common.Address{}, fmt.Errorf("invalid signature type %d", sigType)
+	}
+}
+
+// BuildOrder builds and signs an order for the exchange of the market.
+func (c *ClobClient) BuildOrder(args OrderArgs, opts OrderOptions) (*SignedOrder, error) {
+	if args.TokenID == nil || args.TokenID.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid token id %v", args.TokenID)
+	}
+	if args.FeeRateBps < 0 || args.Nonce < 0 {
+		return nil, fmt.Errorf("fee rate and nonce must not be negative")
+	}
+
+	makerAmount, takerAmount, err := OrderAmounts(args.Side, args.Price, args.Size, opts.TickSize)
+	if err != nil {
+		return nil, err
+	}
+
+	maker, err := c.maker(opts.SignatureType)
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 52))
+	if err != nil {
+		return nil, fmt.Errorf("can't generate order salt: %w", err)
+	}
+
+	expiration := new(big.Int)
+	if !args.Expiration.IsZero() {
+		expiration.SetInt64(args.Expiration.Unix())
+	}
+
+	side := uint8(0)
+	if args.Side == Sell {
+		side = 1
+	}
+
+	order := Order{
+		Salt:          salt,
+		Maker:         maker,
+		Signer:        c.Wallet.Address,
+		Taker:         args.Taker,
+		TokenID:       args.TokenID,
+		MakerAmount:   makerAmount,
+		TakerAmount:   takerAmount,
+		Expiration:    expiration,
+		Nonce:         big.NewInt(args.Nonce),
+		FeeRateBps:    big.NewInt(args.FeeRateBps),
+		Side:          side,
+		SignatureType: uint8(opts.SignatureType),
+	}
+
+	return c.signOrder(order, opts.NegRisk)
+}
+
+// signOrder signs order for the CTF or the neg-risk exchange.
+func (c *ClobClient) signOrder(order Order, negRisk bool) (*SignedOrder, error) {
+	domain := ExchangeDomain(c.Wallet.Network, negRisk)
+
+	signature, err := signTyped(c.Wallet, domain, "Order", order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedOrder{Order: order, Signature: signature, Exchange: domain.VerifyingContract}, nil
+}
@@ -0,0 +1,231 @@
+package polymarket
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"polymarket/internal/web3"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestOrderAmounts follows the official clients' rounding config: sizes
+// have 2 decimals and prices as many as the tick size (0.1: 1, 0.01: 2,
+// 0.001: 3, 0.0001: 4), so the USDC amount has at most 3, 4, 5 or 6
+// decimals and is never rounded.
+func TestOrderAmounts(t *testing.T) {
+	tests := []struct {
+		tick  TickSize
+		side  Side
+		price string
+		size  string
+		maker int64
+		taker int64
+	}{
+		{Tick01, Buy, "0.5", "10", 5_000_000, 10_000_000},
+		{Tick01, Sell, "0.3", "3.333", 3_330_000, 999_000},
+		{Tick01, Buy, "0.50", "1", 500_000, 1_000_000},
+		{Tick001, Buy, "0.56", "21.04", 11_782_400, 21_040_000},
+		{Tick001, Sell, "0.99", "100.019", 100_010_000, 99_009_900},
+		{Tick0001, Buy, "0.123", "7.77", 955_710, 7_770_000},
+		{Tick0001, Sell, "0.999", "1", 1_000_000, 999_000},
+		{Tick00001, Buy, "0.0001", "0.01", 1, 10_000},
+		{Tick00001, Sell, "0.9999", "12.34", 12_340_000, 12_338_766},
+	}
+
+	for _, tt := range tests {
+		maker, taker, err := OrderAmounts(tt.side, tt.price, tt.size, tt.tick)
+		if err != nil {
+			t.Errorf("%s %s @ %s (tick %s): %v", tt.side, tt.size, tt.price, tt.tick, err)
+			continue
+		}
+		if maker.Int64() != tt.maker || taker.Int64() != tt.taker {
+			t.Errorf("%s %s @ %s (tick %s): maker %s taker %s, want %d %d", tt.side, tt.size, tt.price, tt.tick, maker, taker, tt.maker, tt.taker)
+		}
+	}
+}
+
+func TestOrderAmountsRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		tick  TickSize
+		side  Side
+		price string
+		size  string
+	}{
+		{"off tick", Tick001, Buy, "0.555", "1"},
+		{"off coarse tick", Tick01, Sell, "0.25", "1"},
+		{"below a tick", Tick0001, Buy, "0.0001", "1"},
+		{"zero price", Tick001, Buy, "0", "1"},
+		{"price of one", Tick001, Sell, "1", "1"},
+		{"price above one", Tick01, Buy, "1.1", "1"},
+		{"size below a cent", Tick001, Buy, "0.5", "0.009"},
+		{"negative size", Tick001, Buy, "0.5", "-1"},
+		{"unknown tick", "0.05", Buy, "0.5", "1"},
+		{"unknown side", Tick001, "HOLD", "0.5", "1"},
+	}
+
+	for _, tt := range tests {
+		if maker, taker, err := OrderAmounts(tt.side, tt.price, tt.size, tt.tick); err == nil {
+			t.Errorf("%s: got maker %s taker %s, want error", tt.name, maker, taker)
+		}
+	}
+}
+
+// chainBackend is a node that only answers its chain id.
+type chainBackend struct {
+	web3.Backend
+	chainID int64
+}
+
+func (b chainBackend) ChainID(context.Context) (*big.Int, error) {
+	return big.NewInt(b.chainID), nil
+}
+
+// amoyClob is a CLOB client signing with the key of clob-order-utils'
+// tests for the Amoy exchanges.
+func amoyClob(t *testing.T) *ClobClient {
+	t.Helper()
+
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	amoy := *web3.Polygon
+	amoy.Name = "amoy"
+	amoy.ChainID = big.NewInt(80002)
+	amoy.CTFExchange = common.HexToAddress("0xdFE02Eb6733538f8Ea35D585af8DE5958AD99E40")
+	amoy.NegRiskExchange = common.HexToAddress("0xC5d563A36AE78145C45a50134d48A1215220f80a")
+
+	wallet := web3.NewWithBackend(chainBackend{chainID: 80002}, web3.NewKeySigner(key))
+	wallet.Network = &amoy
+
+	return &ClobClient{Wallet: wallet}
+}
+
+// TestSignOrderVector signs the order of clob-order-utils' tests and
+// compares the digest and signature with theirs.
+func TestSignOrderVector(t *testing.T) {
+	clob := amoyClob(t)
+	signer := clob.Wallet.Address
+	if signer != common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266") {
+		t.Fatalf("signer %s", signer)
+	}
+
+	order := Order{
+		Salt:          big.NewInt(479249096354),
+		Maker:         signer,
+		Signer:        signer,
+		TokenID:       big.NewInt(1234),
+		MakerAmount:   big.NewInt(100000000),
+		TakerAmount:   big.NewInt(50000000),
+		Expiration:    big.NewInt(0),
+		Nonce:         big.NewInt(0),
+		FeeRateBps:    big.NewInt(100),
+		Side:          0,
+		SignatureType: uint8(SignatureEOA),
+	}
+
+	data, err := web3.NewTypedData(ExchangeDomain(clob.Wallet.Network, false), "Order", order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := web3.TypedDataHash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToHash("0x02ca1d1aa31103804173ad1acd70066cb6c1258a4be6dada055111f9a7ea4e55"); hash != want {
+		t.Errorf("order hash %s, want %s", hash, want)
+	}
+
+	signed, err := clob.signOrder(order, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0x302cd9abd0b5fcaa202a344437ec0b6660da984e24ae9ad915a592a90facf5a51bb8a873cd8d270f070217fea1986531d5eec66f1162a81f66e026db653bf7ce1c"; signed.Signature != want {
+		t.Errorf("signature %s, want %s", signed.Signature, want)
+	}
+	if signed.Exchange != clob.Wallet.Network.CTFExchange {
+		t.Errorf("signed for %s, want the CTF exchange", signed.Exchange)
+	}
+
+	// The same order for the neg-risk exchange is a different digest.
+	negRisk, err := clob.signOrder(order, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if negRisk.Exchange != clob.Wallet.Network.NegRiskExchange || negRisk.Signature == signed.Signature {
+		t.Errorf("neg-risk order signed for %s with %s", negRisk.Exchange, negRisk.Signature)
+	}
+	verifyOrder(t, negRisk, big.NewInt(80002))
+}
+
+// verifyOrder checks the signature of order against the domain of the
+// exchange it names.
+func verifyOrder(t *testing.T, order *SignedOrder, chainID *big.Int) {
+	t.Helper()
+
+	domain := web3.Domain{Name: "Polymarket CTF Exchange", Version: "1", ChainID: chainID, VerifyingContract: order.Exchange}
+	data, err := web3.NewTypedData(domain, "Order", order.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := web3.VerifyTypedData(order.Signer, data, hexutil.MustDecode(order.Signature)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBuildOrder(t *testing.T) {
+	clob := amoyClob(t)
+	owner := clob.Wallet.Address
+	network := clob.Wallet.Network
+
+	safe, err := network.ProxyAddress(web3.ProxySafe, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := network.ProxyAddress(web3.ProxyPolymarket, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sigType SignatureType
+		negRisk bool
+		maker   common.Address
+	}{
+		{SignatureEOA, false, owner},
+		{SignaturePolyProxy, false, proxy},
+		{SignaturePolyGnosisSafe, false, safe},
+		{SignaturePolyGnosisSafe, true, safe},
+	}
+
+	args := OrderArgs{TokenID: big.NewInt(1234), Side: Sell, Price: "0.42", Size: "10", FeeRateBps: 100, Nonce: 3}
+
+	for _, tt := range tests {
+		order, err := clob.BuildOrder(args, OrderOptions{TickSize: Tick001, NegRisk: tt.negRisk, SignatureType: tt.sigType})
+		if err != nil {
+			t.Fatalf("signature type %d: %v", tt.sigType, err)
+		}
+
+		if order.Maker != tt.maker || order.Signer != owner || order.SignatureType != uint8(tt.sigType) {
+			t.Errorf("signature type %d: maker %s signer %s type %d, want maker %s signed by %s",
+				tt.sigType, order.Maker, order.Signer, order.SignatureType, tt.maker, owner)
+		}
+		if want := network.Exchange(tt.negRisk); order.Exchange != want {
+			t.Errorf("neg risk %v: signed for %s, want %s", tt.negRisk, order.Exchange, want)
+		}
+		if order.Side != 1 || order.MakerAmount.Int64() != 10_000_000 || order.TakerAmount.Int64() != 4_200_000 {
+			t.Errorf("side %d maker %s taker %s, want a sell of 10 for 4.2", order.Side, order.MakerAmount, order.TakerAmount)
+		}
+		verifyOrder(t, order, network.ChainID)
+	}
+
+	if _, err := clob.BuildOrder(args, OrderOptions{TickSize: Tick001, SignatureType: 7}); err == nil {
+		t.Error("unknown signature type was accepted")
+	}
+}
@@ -0,0 +1,380 @@
+package polymarket
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type OrderType string
+
+const (
+	// GTC rests until filled or cancelled, GTD until its expiration.
+	GTC OrderType = "GTC"
+	GTD OrderType = "GTD"
+	// FOK fills completely at once or is cancelled, FAK fills what it can
+	// at once and cancels the rest.
+	FOK OrderType = "FOK"
+	FAK OrderType = "FAK"
+)
+
+// Reasons the CLOB rejects an order for, matched with errors.Is against an
+// OrderError.
+var (
+	ErrInsufficientBalance = errors.New("not enough balance or allowance")
+	ErrInvalidTick         = errors.New("price breaks the market tick size")
+	ErrMinSize             = errors.New("size below the market minimum")
+	ErrInvalidExpiration   = errors.New("invalid expiration")
+	ErrDuplicateOrder      = errors.New("duplicate order")
+	ErrNotFilled           = errors.New("order could not be filled")
+	ErrPostOnlyCrosses     = errors.New("post-only order crosses the book")
+	ErrMarketClosed        = errors.New("market not accepting orders")
+	ErrOrderRejected       = errors.New("order rejected")
+)
+
+// orderErrorReasons maps CLOB error messages to reasons, first match wins.
+var orderErrorReasons = []struct {
+	match  string
+	reason error
+}{
+	{"not enough balance", ErrInsufficientBalance},
+	{"not_enough_balance", ErrInsufficientBalance},
+	{"allowance", ErrInsufficientBalance},
+	{"tick size", ErrInvalidTick},
+	{"min_tick_size", ErrInvalidTick},
+	{"min_size", ErrMinSize},
+	{"lower than the minimum", ErrMinSize},
+	{"expiration", ErrInvalidExpiration},
+	{"duplicated", ErrDuplicateOrder},
+	{"fok_order_not_filled", ErrNotFilled},
+	{"no orders found to match", ErrNotFilled},
+	{"post-only", ErrPostOnlyCrosses},
+	{"crosses book", ErrPostOnlyCrosses},
+	{"market_not_ready", ErrMarketClosed},
+	{"not yet ready to process new orders", ErrMarketClosed},
+	{"market is closed", ErrMarketClosed},
+	{"orderbook is closed", ErrMarketClosed},
+}
+
+// OrderError is an order the CLOB refused. Reason is one of the Err*
+// reasons above.
+type OrderError struct {
+	Reason  error
+	Message string
+}
+
+func (e *OrderError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Reason, e.Message)
+}
+
+func (e *OrderError) Unwrap() error {
+	return e.Reason
+}
+
+func newOrderError(message string) *OrderError {
+	lower := strings.ToLower(message)
+	for _, r := range orderErrorReasons {
+		if strings.Contains(lower, r.match) {
+			return &OrderError{Reason: r.reason, Message: message}
+		}
+	}
+
+	return &OrderError{Reason: ErrOrderRejected, Message: message}
+}
+
+// orderError turns a rejected post into an OrderError, other errors are
+// returned as they are.
+func orderError(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusBadRequest {
+		return newOrderError(apiErr.Message)
+	}
+
+	return err
+}
+
+// PostArgs is a signed order and how to place it.
+type PostArgs struct {
+	Order     *SignedOrder
+	OrderType OrderType
+	PostOnly  bool
+}
+
+// gtdThreshold is how far in the future the CLOB wants a GTD expiration.
+const gtdThreshold = time.Minute
+
+func (a PostArgs) validate(now time.Time) error {
+	if a.Order == nil {
+		return errors.New("no order to post")
+	}
+
+	expiration := int64(0)
+	if a.Order.Expiration != nil {
+		if !a.Order.Expiration.IsInt64() {
+			return &OrderError{Reason: ErrInvalidExpiration, Message: fmt.Sprintf("expiration %s out of range", a.Order.Expiration)}
+		}
+		expiration = a.Order.Expiration.Int64()
+	}
+	expires := expiration != 0
+
+	switch a.OrderType {
+	case GTD:
+		if !expires {
+			return &OrderError{Reason: ErrInvalidExpiration, Message: "GTD order without expiration"}
+		}
+		if min := now.Add(gtdThreshold).Unix(); expiration <= min {
+			return &OrderError{Reason: ErrInvalidExpiration, Message: fmt.Sprintf("GTD expiration %d must be after %d, a minute from now", expiration, min)}
+		}
+	case GTC, FOK, FAK:
+		if expires {
+			return &OrderError{Reason: ErrInvalidExpiration, Message: fmt.Sprintf("%s order with expiration", a.OrderType)}
+		}
+	default:
+		return fmt.Errorf("invalid order type %q", a.OrderType)
+	}
+
+	if a.PostOnly && a.OrderType != GTC && a.OrderType != GTD {
+		return fmt.Errorf("post-only needs a GTC or GTD order, got %s", a.OrderType)
+	}
+
+	return nil
+}
+
+type orderPayload struct {
+	Salt          int64  `json:"salt"`
+	Maker         string `json:"maker"`
+	Signer        string `json:"signer"`
+	Taker         string `json:"taker"`
+	TokenID       string `json:"tokenId"`
+	MakerAmount   string `json:"makerAmount"`
+	TakerAmount   string `json:"takerAmount"`
+	Expiration    string `json:"expiration"`
+	Nonce         string `json:"nonce"`
+	FeeRateBps    string `json:"feeRateBps"`
+	Side          Side   `json:"side"`
+	SignatureType uint8  `json:"signatureType"`
+	Signature     string `json:"signature"`
+}
+
+type postOrderPayload struct {
+	Order     orderPayload `json:"order"`
+	Owner     string       `json:"owner"`
+	OrderType OrderType    `json:"orderType"`
+	PostOnly  bool         `json:"postOnly"`
+}
+
+func (a PostArgs) payload(owner string) postOrderPayload {
+	o := a.Order
+
+	side := Buy
+	if o.Side == 1 {
+		side = Sell
+	}
+
+	return postOrderPayload{
+		Order: orderPayload{
+			Salt:          o.Salt.Int64(),
+			Maker:         o.Maker.Hex(),
+			Signer:        o.Signer.Hex(),
+			Taker:         o.Taker.Hex(),
+			TokenID:       o.TokenID.String(),
+			MakerAmount:   o.MakerAmount.String(),
+			TakerAmount:   o.TakerAmount.String(),
+			Expiration:    o.Expiration.String(),
+			Nonce:         o.Nonce.String(),
+			FeeRateBps:    o.FeeRateBps.String(),
+			Side:          side,
+			SignatureType: o.SignatureType,
+			Signature:     o.Signature,
+		},
+		Owner:     owner,
+		OrderType: a.OrderType,
+		PostOnly:  a.PostOnly,
+	}
+}
+
+// PostResult is the CLOB's answer to a placed order. Status is live,
+// matched, delayed or unmatched.
+type PostResult struct {
+	Success            bool     `json:"success"`
+	ErrorMsg           string   `json:"errorMsg"`
+	OrderID            string   `json:"orderID"`
+	Status             string   `json:"status"`
+	MakingAmount       Decimal  `json:"makingAmount"`
+	TakingAmount       Decimal  `json:"takingAmount"`
+	TransactionsHashes []string `json:"transactionsHashes"`
+}
+
+// Err is the OrderError of a result that did not succeed.
+func (r *PostResult) Err() error {
+	if r.Success && r.ErrorMsg == "" {
+		return nil
+	}
+
+	return newOrderError(r.ErrorMsg)
+}
+
+// CancelResult lists cancelled orders and why others were not cancelled.
+type CancelResult struct {
+	Canceled    []string          `json:"canceled"`
+	NotCanceled map[string]string `json:"not_canceled"`
+}
+
+// OpenOrder is a resting order of the wallet.
+type OpenOrder struct {
+	ID           string    `json:"id"`
+	Status       string    `json:"status"`
+	Owner        string    `json:"owner"`
+	MakerAddress string    `json:"maker_address"`
+	Market       string    `json:"market"`
+	AssetID      string    `json:"asset_id"`
+	Side         Side      `json:"side"`
+	OriginalSize Decimal   `json:"original_size"`
+	SizeMatched  Decimal   `json:"size_matched"`
+	Price        Decimal   `json:"price"`
+	Outcome      string    `json:"outcome"`
+	Expiration   string    `json:"expiration"`
+	OrderType    OrderType `json:"order_type"`
+	CreatedAt    int64     `json:"created_at"`
+}
+
+// OpenOrderFilter narrows GetOpenOrders to an order, a market or a token.
+type OpenOrderFilter struct {
+	ID      string
+	Market  string
+	AssetID string
+}
+
+// endCursor marks the last page of a paginated CLOB listing.
+const endCursor = "LTE="
+
+// PostOrder places a signed order.
+func (c *ClobClient) PostOrder(args PostArgs) (*PostResult, error) {
+	if err := args.validate(time.Now()); err != nil {
+		return nil, err
+	}
+
+	creds, err := c.ApiCreds()
+	if err != nil {
+		return nil, err
+	}
+
+	var result PostResult
+	if err := c.doL2(http.MethodPost, "/order", args.payload(creds.Key), &result); err != nil {
+		return nil, orderError(err)
+	}
+
+	return &result, result.Err()
+}
+
+// PostOrders places several signed orders in one request. Every order gets
+// its own result; check each with Err.
+func (c *ClobClient) PostOrders(args []PostArgs) ([]PostResult, error) {
+	creds, err := c.ApiCreds()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := make([]postOrderPayload, len(args))
+	for i, a := range args {
+		if err := a.validate(time.Now()); err != nil {
+			return nil, fmt.Errorf("order %d: %w", i, err)
+		}
+		payload[i] = a.payload(creds.Key)
+	}
+
+	var results []PostResult
+	if err := c.doL2(http.MethodPost, "/orders", payload, &results); err != nil {
+		return nil, orderError(err)
+	}
+
+	return results, nil
+}
+
+func (c *ClobClient) CancelOrder(orderID string) (*CancelResult, error) {
+	var result CancelResult
+	if err := c.doL2(http.MethodDelete, "/order", map[string]string{"orderID": orderID}, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *ClobClient) CancelOrders(orderIDs []string) (*CancelResult, error) {
+	var result CancelResult
+	if err := c.doL2(http.MethodDelete, "/orders", orderIDs, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CancelAll cancels every open order of the wallet.
+func (c *ClobClient) CancelAll() (*CancelResult, error) {
+	var result CancelResult
+	if err := c.doL2(http.MethodDelete, "/cancel-all", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CancelMarketOrders cancels the open orders in a market (condition id),
+// or only those of one of its tokens when assetID is set.
+func (c *ClobClient) CancelMarketOrders(market, assetID string) (*CancelResult, error) {
+	body := map[string]string{"market": market, "asset_id": assetID}
+
+	var result CancelResult
+	if err := c.doL2(http.MethodDelete, "/cancel-market-orders", body, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetOpenOrders lists the wallet's open orders matching filter, following
+// pagination to the end.
+func (c *ClobClient) GetOpenOrders(filter OpenOrderFilter) ([]OpenOrder, error) {
+	var orders []OpenOrder
+
+	cursor := ""
+	for cursor != endCursor {
+		query := url.Values{}
+		if filter.ID != "" {
+			query.Set("id", filter.ID)
+		}
+		if filter.Market != "" {
+			query.Set("market", filter.Market)
+		}
+		if filter.AssetID != "" {
+			query.Set("asset_id", filter.AssetID)
+		}
+		if cursor != "" {
+			query.Set("next_cursor", cursor)
+		}
+
+		path := "/data/orders"
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+
+		var page struct {
+			Data       []OpenOrder `json:"data"`
+			NextCursor string      `json:"next_cursor"`
+		}
+		if err := c.doL2(http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		orders = append(orders, page.Data...)
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,319 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"polymarket/internal/web3"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestNewOrderError(t *testing.T) {
+	tests := []struct {
+		message string
+		reason  error
+	}{
+		{"not enough balance / allowance", ErrInsufficientBalance},
+		{"INVALID_ORDER_NOT_ENOUGH_BALANCE", ErrInsufficientBalance},
+		{"invalid order: price breaks minimum tick size rules", ErrInvalidTick},
+		{"INVALID_ORDER_MIN_TICK_SIZE", ErrInvalidTick},
+		{"INVALID_ORDER_MIN_SIZE", ErrMinSize},
+		{"Size (1) lower than the minimum: 5", ErrMinSize},
+		{"INVALID_ORDER_EXPIRATION", ErrInvalidExpiration},
+		{"INVALID_ORDER_DUPLICATED", ErrDuplicateOrder},
+		{"FOK_ORDER_NOT_FILLED_ERROR", ErrNotFilled},
+		{"no orders found to match with FAK order", ErrNotFilled},
+		{"invalid post-only order: order crosses book", ErrPostOnlyCrosses},
+		{"MARKET_NOT_READY", ErrMarketClosed},
+		{"the market is not yet ready to process new orders", ErrMarketClosed},
+		{"market is closed", ErrMarketClosed},
+		{"the orderbook is closed", ErrMarketClosed},
+		{"order closed by user before fill", ErrOrderRejected},
+		{"disclosed reason", ErrOrderRejected},
+		{"", ErrOrderRejected},
+	}
+
+	for _, tt := range tests {
+		err := newOrderError(tt.message)
+		if !errors.Is(err, tt.reason) {
+			t.Errorf("%q: reason %v, want %v", tt.message, err.Reason, tt.reason)
+		}
+		if err.Message != tt.message {
+			t.Errorf("%q: message %q", tt.message, err.Message)
+		}
+	}
+}
+
+func TestPostArgsValidate(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	order := func(expiration int64) *SignedOrder {
+		return &SignedOrder{Order: Order{Expiration: big.NewInt(expiration)}}
+	}
+
+	tests := []struct {
+		name   string
+		args   PostArgs
+		ok     bool
+		reason error
+	}{
+		{"gtc", PostArgs{Order: order(0), OrderType: GTC}, true, nil},
+		{"gtc post-only", PostArgs{Order: order(0), OrderType: GTC, PostOnly: true}, true, nil},
+		{"fok", PostArgs{Order: order(0), OrderType: FOK}, true, nil},
+		{"fak", PostArgs{Order: order(0), OrderType: FAK}, true, nil},
+		{"nil expiration", PostArgs{Order: &SignedOrder{}, OrderType: GTC}, true, nil},
+		{"gtd", PostArgs{Order: order(now.Unix() + 61), OrderType: GTD}, true, nil},
+		{"gtd post-only", PostArgs{Order: order(now.Unix() + 3600), OrderType: GTD, PostOnly: true}, true, nil},
+		{"nil order", PostArgs{OrderType: GTC}, false, nil},
+		{"unknown type", PostArgs{Order: order(0), OrderType: "IOC"}, false, nil},
+		{"fok post-only", PostArgs{Order: order(0), OrderType: FOK, PostOnly: true}, false, nil},
+		{"fak post-only", PostArgs{Order: order(0), OrderType: FAK, PostOnly: true}, false, nil},
+		{"gtc with expiration", PostArgs{Order: order(now.Unix() + 3600), OrderType: GTC}, false, ErrInvalidExpiration},
+		{"fok with expiration", PostArgs{Order: order(now.Unix() + 3600), OrderType: FOK}, false, ErrInvalidExpiration},
+		{"gtd without expiration", PostArgs{Order: order(0), OrderType: GTD}, false, ErrInvalidExpiration},
+		{"gtd at the threshold", PostArgs{Order: order(now.Unix() + 60), OrderType: GTD}, false, ErrInvalidExpiration},
+		{"gtd in the past", PostArgs{Order: order(now.Unix() - 1), OrderType: GTD}, false, ErrInvalidExpiration},
+		{"gtd out of range", PostArgs{Order: &SignedOrder{Order: Order{Expiration: new(big.Int).Lsh(big.NewInt(1), 64)}}, OrderType: GTD}, false, ErrInvalidExpiration},
+	}
+
+	for _, tt := range tests {
+		err := tt.args.validate(now)
+		if tt.ok {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: no error", tt.name)
+			continue
+		}
+		if tt.reason != nil && !errors.Is(err, tt.reason) {
+			t.Errorf("%s: %v, want %v", tt.name, err, tt.reason)
+		}
+	}
+}
+
+var testCreds = &ApiCreds{Key: "key", Secret: "c2VjcmV0LXNlY3JldC1zZWNyZXQtc2VjcmV0IQ==", Passphrase: "pass"}
+
+// newTestClob is a CLOB client of a fresh Polygon wallet holding testCreds,
+// talking to handler.
+func newTestClob(t *testing.T, handler http.HandlerFunc) *ClobClient {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet := web3.NewWithBackend(chainBackend{chainID: 137}, web3.NewKeySigner(key))
+
+	store := NewMemoryStore()
+	if err := store.Save(wallet.Address, testCreds); err != nil {
+		t.Fatal(err)
+	}
+
+	clob := NewClob(wallet, store)
+	clob.Host = server.URL
+
+	return clob
+}
+
+func testSignedOrder(t *testing.T, clob *ClobClient, side Side) *SignedOrder {
+	t.Helper()
+
+	order, err := clob.BuildOrder(OrderArgs{TokenID: big.NewInt(1234), Side: side, Price: "0.42", Size: "10"}, OrderOptions{TickSize: Tick001})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return order
+}
+
+func TestPostOrder(t *testing.T) {
+	var order *SignedOrder
+
+	clob := newTestClob(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/order" {
+			t.Errorf("request %s %s", r.Method, r.URL)
+		}
+
+		var body struct {
+			Order     map[string]any `json:"order"`
+			Owner     string         `json:"owner"`
+			OrderType string         `json:"orderType"`
+			PostOnly  bool           `json:"postOnly"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if body.Owner != testCreds.Key || body.OrderType != "GTC" || !body.PostOnly {
+			t.Errorf("owner %q type %q post-only %v, want the api key, GTC and post-only", body.Owner, body.OrderType, body.PostOnly)
+		}
+
+		want := map[string]any{
+			"salt":          float64(order.Salt.Int64()),
+			"maker":         order.Maker.Hex(),
+			"signer":        order.Signer.Hex(),
+			"taker":         common.Address{}.Hex(),
+			"tokenId":       "1234",
+			"makerAmount":   "10000000",
+			"takerAmount":   "4200000",
+			"expiration":    "0",
+			"nonce":         "0",
+			"feeRateBps":    "0",
+			"side":          "SELL",
+			"signatureType": float64(0),
+			"signature":     order.Signature,
+		}
+		if !reflect.DeepEqual(body.Order, want) {
+			t.Errorf("order %v, want %v", body.Order, want)
+		}
+
+		w.Write([]byte(`{"success":true,"errorMsg":"","orderID":"0xabc","status":"live","makingAmount":"","takingAmount":"","transactionsHashes":[]}`))
+	})
+	order = testSignedOrder(t, clob, Sell)
+
+	result, err := clob.PostOrder(PostArgs{Order: order, OrderType: GTC, PostOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.OrderID != "0xabc" || result.Status != "live" {
+		t.Errorf("result %+v", result)
+	}
+}
+
+func TestPostOrderRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		reason error
+	}{
+		{"bad request", http.StatusBadRequest, `{"error":"not enough balance / allowance"}`, ErrInsufficientBalance},
+		{"bad request without reason", http.StatusBadRequest, `{"error":"invalid signature"}`, ErrOrderRejected},
+		{"unsuccessful", http.StatusOK, `{"success":false,"errorMsg":"INVALID_ORDER_MIN_TICK_SIZE"}`, ErrInvalidTick},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clob := newTestClob(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := clob.PostOrder(PostArgs{Order: testSignedOrder(t, clob, Buy), OrderType: GTC})
+
+			var orderErr *OrderError
+			if !errors.As(err, &orderErr) || !errors.Is(err, tt.reason) {
+				t.Fatalf("err %v, want an OrderError for %v", err, tt.reason)
+			}
+		})
+	}
+
+	// Other failures are not the order's fault.
+	clob := newTestClob(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"internal"}`, http.StatusInternalServerError)
+	})
+	_, err := clob.PostOrder(PostArgs{Order: testSignedOrder(t, clob, Buy), OrderType: GTC})
+
+	var orderErr *OrderError
+	var apiErr *APIError
+	if errors.As(err, &orderErr) || !errors.As(err, &apiErr) || apiErr.Status != http.StatusInternalServerError {
+		t.Errorf("err %v, want the APIError", err)
+	}
+}
+
+func TestPostOrders(t *testing.T) {
+	clob := newTestClob(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/orders" {
+			t.Errorf("request %s %s", r.Method, r.URL)
+		}
+
+		var body []struct {
+			Owner     string `json:"owner"`
+			OrderType string `json:"orderType"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(body) != 2 || body[0].Owner != testCreds.Key || body[1].OrderType != "FOK" {
+			t.Errorf("body %+v", body)
+		}
+
+		w.Write([]byte(`[
+			{"success":true,"errorMsg":"","orderID":"0x1","status":"matched","makingAmount":"4.2","takingAmount":"10"},
+			{"success":false,"errorMsg":"FOK_ORDER_NOT_FILLED_ERROR","orderID":"","status":""}
+		]`))
+	})
+
+	results, err := clob.PostOrders([]PostArgs{
+		{Order: testSignedOrder(t, clob, Buy), OrderType: GTC},
+		{Order: testSignedOrder(t, clob, Buy), OrderType: FOK},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("%d results, want 2", len(results))
+	}
+
+	if err := results[0].Err(); err != nil || results[0].MakingAmount.String() != "4.2" {
+		t.Errorf("first result %+v: %v", results[0], err)
+	}
+	if err := results[1].Err(); !errors.Is(err, ErrNotFilled) {
+		t.Errorf("second result err %v, want %v", err, ErrNotFilled)
+	}
+
+	if _, err := clob.PostOrders([]PostArgs{{Order: testSignedOrder(t, clob, Buy), OrderType: GTD}}); err == nil {
+		t.Error("invalid order was posted")
+	}
+}
+
+func TestGetOpenOrders(t *testing.T) {
+	pages := map[string]string{
+		"":     `{"data":[{"id":"0x1","price":"0.42","original_size":"10","size_matched":"0","side":"BUY"}],"next_cursor":"MTAw"}`,
+		"MTAw": `{"data":[{"id":"0x2","price":"0.5","original_size":"5","size_matched":"2.5","side":"SELL"}],"next_cursor":"LTE="}`,
+	}
+	requests := 0
+
+	clob := newTestClob(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.URL.Path != "/data/orders" || r.URL.Query().Get("market") != "0xabc" {
+			t.Errorf("request %s", r.URL)
+		}
+
+		page, ok := pages[r.URL.Query().Get("next_cursor")]
+		if !ok {
+			t.Errorf("unknown cursor in %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(page))
+	})
+
+	orders, err := clob.GetOpenOrders(OpenOrderFilter{Market: "0xabc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("%d requests, want 2", requests)
+	}
+	if len(orders) != 2 || orders[0].ID != "0x1" || orders[1].ID != "0x2" || orders[1].SizeMatched.String() != "2.5" {
+		t.Errorf("orders %+v", orders)
+	}
+}
@@ -0,0 +1,110 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"polymarket/internal/web3"
+
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RedeemReport lists the conditions a redemption cashed out and the USDC.e
+// the proxy received for them, read from the mined transaction.
+type RedeemReport struct {
+	Conditions []common.Hash
+	Received   web3.Amount
+}
+
+// Redeem redeems every resolved position held by the wallet's Safe proxy in
+// one relayed batch. Candidates come from the data API; balances and
+// payouts are checked on chain. If the batch is still pending the report
+// lists the submitted conditions with nothing received, together with
+// ErrTxPending.
+func Redeem(pc *Client, wc *web3.Wallet, session *Session) (*RedeemReport, error) {
+	ctx := context.Background()
+
+	safe, err := wc.Safe()
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := pc.GetPositions(safe.Address.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := redeemCandidates(held)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RedeemReport{Received: web3.NewAmount(nil, web3.USDCDecimals)}
+	if len(candidates) == 0 {
+		return report, nil
+	}
+
+	positions, err := wc.CTF().Positions(ctx, safe.Address, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	calls, conditions, err := wc.RedeemCalls(positions)
+	if err != nil {
+		return nil, err
+	}
+	if len(calls) == 0 {
+		log.Printf("Nothing to redeem | %s", safe.Address)
+		return report, nil
+	}
+
+	result, err := pc.ExecSafeBatch(ctx, wc, calls, session.PolyNonce, session.PolySession)
+	if errors.Is(err, ErrTxPending) {
+		report.Conditions = conditions
+		return report, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.Conditions = conditions
+
+	waitCtx, cancel := context.WithTimeout(ctx, RelayerTimeout)
+	defer cancel()
+
+	mined, err := wc.WaitMined(waitCtx, common.HexToHash(result.TransactionHash), 1)
+	if err != nil {
+		return report, err
+	}
+
+	report.Received = wc.USDC().ReceivedIn(mined.Receipt, safe.Address)
+
+	for _, condition := range conditions {
+		log.Printf("Redeemed %s | %s", condition, safe.Address)
+	}
+	log.Printf("Received %s USDC.e | %s", report.Received, safe.Address)
+
+	return report, nil
+}
+
+// redeemCandidates are the positions of the data API entries, each once;
+// pages read while positions change can repeat an entry.
+func redeemCandidates(held []DataPosition) ([]web3.Position, error) {
+	var candidates []web3.Position
+	seen := make(map[string]bool)
+
+	for _, p := range held {
+		position, err := p.Position()
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[position.ID.String()] {
+			continue
+		}
+		seen[position.ID.String()] = true
+
+		candidates = append(candidates, position)
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,169 @@
+package polymarket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"polymarket/internal/web3"
+
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const relayerURL = "https://relayer-v2.polymarket.com"
+
+// GetRelayerNonce returns the next Safe nonce the relayer expects from
+// owner. It counts transactions the relayer has queued but not mined yet.
+func (c *Client) GetRelayerNonce(owner, polyNonce, polySession string) (*big.Int, error) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/nonce?address=%s&type=SAFE", relayerURL, owner), nil)
+	setRelayerHeaders(req, polyNonce, polySession)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error when request relayer nonce: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error when read body relayer nonce: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("relayer nonce status %d: %s", resp.StatusCode, body)
+	}
+
+	var data RespRelayerNonce
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("error when unmarshal relayer nonce: %w", err)
+	}
+
+	nonce, ok := new(big.Int).SetString(data.Nonce, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid relayer nonce %q", data.Nonce)
+	}
+
+	return nonce, nil
+}
+
+// ExecSafeTx has the wallet's Safe proxy call to with data through the
+// relayer, which pays the gas, and waits until it is mined, see
+// CheckTxStatus. The result carries the relayer's transaction id even when
+// waiting fails.
+func (c *Client) ExecSafeTx(ctx context.Context, wallet *web3.Wallet, to common.Address, data []byte, operation uint8, polyNonce, polySession string) (*RespEnableTrade, error) {
+	safe, err := wallet.Safe()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := c.GetRelayerNonce(wallet.Address.Hex(), polyNonce, polySession)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := web3.NewSafeTx(to, nil, data, operation, nonce)
+
+	signature, err := safe.SignRelayed(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := PayloadSafeTx{
+		From:        wallet.Address.Hex(),
+		To:          to.Hex(),
+		ProxyWallet: safe.Address.Hex(),
+		Data:        hexutil.Encode(data),
+		Nonce:       nonce.String(),
+		Signature:   hexutil.Encode(signature),
+		SignatureParams: SafeSignatureParams{
+			GasPrice:       "0",
+			Operation:      fmt.Sprint(operation),
+			SafeTxGas:      "0",
+			BaseGas:        "0",
+			GasToken:       common.Address{}.Hex(),
+			RefundReceiver: common.Address{}.Hex(),
+		},
+		Type: "SAFE",
+	}
+
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error when marshal safe tx: %w", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, relayerURL+"/submit", bytes.NewBuffer(bodyBytes))
+	setRelayerHeaders(req, polyNonce, polySession)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error when submit safe tx: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error when read body submit safe tx: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("relayer rejected safe tx, status %d: %s", resp.StatusCode, body)
+	}
+
+	var result RespEnableTrade
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("error when unmarshal submit safe tx: %w", err)
+	}
+
+	log.Printf("Safe tx submitted | %s | %s", safe.Address, result.TransactionID)
+
+	hash, err := c.CheckTxStatus(ctx, result.TransactionID, polyNonce, polySession)
+	if err != nil {
+		return &result, err
+	}
+	result.TransactionHash = hash.Hex()
+
+	return &result, nil
+}
+
+func setRelayerHeaders(req *http.Request, polyNonce, polySession string) {
+	req.Header.Set("accept", "application/json, text/plain, */*")
+	req.Header.Set("accept-language", "ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7")
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("cookie", fmt.Sprintf("AMP_MKTG_4572e28e5c=JTdCJTIycmVmZXJyZXIlMjIlM0ElMjJodHRwcyUzQSUyRiUyRnd3dy5nb29nbGUuY29tJTJGJTIyJTJDJTIycmVmZXJyaW5nX2RvbWFpbiUyMiUzQSUyMnd3dy5nb29nbGUuY29tJTIyJTdE; polymarketnonce=%s; polymarketsession=%s; polymarketauthtype=metamask; AMP_4572e28e5c=JTdCJTdE", polyNonce, polySession))
+	req.Header.Set("origin", "https://polymarket.com")
+	req.Header.Set("priority", "u=1, i")
+	req.Header.Set("sec-ch-ua", `"Google Chrome";v="129", "Not=A?Brand";v="8", "Chromium";v="129"`)
+	req.Header.Set("sec-ch-ua-mobile", "?0")
+	req.Header.Set("sec-ch-ua-platform", `"Windows"`)
+	req.Header.Set("sec-fetch-dest", "empty")
+	req.Header.Set("sec-fetch-mode", "cors")
+	req.Header.Set("sec-fetch-site", "same-site")
+	req.Header.Set("user-agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36")
+}
+
+// ExecSafeBatch has the Safe proxy run calls as one MultiSendCallOnly
+// delegatecall through the relayer: one signature and one submission for
+// the batch.
+func (c *Client) ExecSafeBatch(ctx context.Context, wallet *web3.Wallet, calls []web3.MultiSendCall, polyNonce, polySession string) (*RespEnableTrade, error) {
+	to, data, err := wallet.Network.MultiSendTx(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := web3.DecodeMultiSend(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, call := range decoded {
+		log.Printf("Batch call %d/%d | %s", i+1, len(decoded), call)
+	}
+
+	return c.ExecSafeTx(ctx, wallet, to, data, web3.SafeDelegateCall, polyNonce, polySession)
+}
@@ -0,0 +1,94 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// redirectTransport sends every request to a test server, whatever host
+// the client asked for.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Client{Client: &http.Client{Transport: redirectTransport{target: target}}}
+}
+
+func TestCheckTxStatus(t *testing.T) {
+	const hash = "0x00000000000000000000000000000000000000000000000000000000000000aa"
+
+	relayerPoll = 10 * time.Millisecond
+	t.Cleanup(func() { relayerPoll = 3 * time.Second })
+
+	tests := []struct {
+		name    string
+		states  []string
+		timeout time.Duration
+		pending bool
+		wantErr bool
+	}{
+		{name: "mined", states: []string{"STATE_NEW", "STATE_EXECUTED", "STATE_MINED"}, timeout: 5 * time.Second},
+		{name: "failed", states: []string{"STATE_NEW", "STATE_FAILED"}, timeout: 5 * time.Second, wantErr: true},
+		{name: "pending", states: []string{"STATE_NEW"}, timeout: 200 * time.Millisecond, pending: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls atomic.Int64
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("id") != "tx-1" {
+					http.NotFound(w, r)
+					return
+				}
+				n := int(polls.Add(1)) - 1
+				state := tt.states[min(n, len(tt.states)-1)]
+				w.Write([]byte(`[{"transactionID":"tx-1","transactionHash":"` + hash + `","state":"` + state + `"}]`))
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			got, err := client.CheckTxStatus(ctx, "tx-1", "", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err %v, want error %t", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrTxPending) != tt.pending {
+				t.Fatalf("err %v, want pending %t", err, tt.pending)
+			}
+			if tt.pending && !strings.Contains(err.Error(), "tx-1") {
+				t.Errorf("pending error %q lacks the tx id", err)
+			}
+			if !tt.wantErr && got != common.HexToHash(hash) {
+				t.Errorf("hash %s, want %s", got, hash)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package polymarket
+
+import (
+	"context"
+	"polymarket/internal/web3"
+
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SplitPosition splits amount of the proxy's USDC.e into amount of YES and
+// NO of conditionID through the relayer. Neg-risk markets are split
+// through the adapter.
+func SplitPosition(pc *Client, wc *web3.Wallet, session *Session, conditionID common.Hash, amount web3.Amount) error {
+	safe, err := wc.Safe()
+	if err != nil {
+		return err
+	}
+
+	market, err := pc.GetMarket(conditionID)
+	if err != nil {
+		return err
+	}
+
+	if err := market.Verify(context.Background(), wc); err != nil {
+		return err
+	}
+
+	calls, err := safe.SplitCalls(context.Background(), conditionID, amount, market.NegRisk)
+	if err != nil {
+		return err
+	}
+
+	if _, err := pc.ExecSafeBatch(context.Background(), wc, calls, session.PolyNonce, session.PolySession); err != nil {
+		return err
+	}
+
+	log.Printf("Split %s USDC.e | %s | %s", amount, conditionID, safe.Address)
+
+	return nil
+}
+
+// MergePositions merges amount of full YES/NO sets of conditionID held by
+// the proxy back into USDC.e through the relayer.
+func MergePositions(pc *Client, wc *web3.Wallet, session *Session, conditionID common.Hash, amount web3.Amount) error {
+	safe, err := wc.Safe()
+	if err != nil {
+		return err
+	}
+
+	market, err := pc.GetMarket(conditionID)
+	if err != nil {
+		return err
+	}
+
+	if err := market.Verify(context.Background(), wc); err != nil {
+		return err
+	}
+
+	calls, err := safe.MergeCalls(context.Background(), conditionID, amount, market.NegRisk)
+	if err != nil {
+		return err
+	}
+
+	if _, err := pc.ExecSafeBatch(context.Background(), wc, calls, session.PolyNonce, session.PolySession); err != nil {
+		return err
+	}
+
+	log.Printf("Merged %s sets | %s | %s", amount, conditionID, safe.Address)
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package polymarket
+
+import (
+	"context"
+	"math/big"
+	"polymarket/internal/web3"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CreateProxy is signed to have the relayer deploy the Safe proxy wallet.
+type CreateProxy struct {
+	PaymentToken    common.Address `eip712:"paymentToken,address"`
+	Payment         *big.Int       `eip712:"payment,uint256"`
+	PaymentReceiver common.Address `eip712:"paymentReceiver,address"`
+}
+
+// ClobAuth is signed for CLOB L1 authentication.
+type ClobAuth struct {
+	Address   common.Address `eip712:"address,address"`
+	Timestamp string         `eip712:"timestamp,string"`
+	Nonce     *big.Int       `eip712:"nonce,uint256"`
+	Message   string         `eip712:"message,string"`
+}
+
+// Order is a CTF Exchange order.
+type Order struct {
+	Salt          *big.Int       `eip712:"salt,uint256"`
+	Maker         common.Address `eip712:"maker,address"`
+	Signer        common.Address `eip712:"signer,address"`
+	Taker         common.Address `eip712:"taker,address"`
+	TokenID       *big.Int       `eip712:"tokenId,uint256"`
+	MakerAmount   *big.Int       `eip712:"makerAmount,uint256"`
+	TakerAmount   *big.Int       `eip712:"takerAmount,uint256"`
+	Expiration    *big.Int       `eip712:"expiration,uint256"`
+	Nonce         *big.Int       `eip712:"nonce,uint256"`
+	FeeRateBps    *big.Int       `eip712:"feeRateBps,uint256"`
+	Side          uint8          `eip712:"side,uint8"`
+	SignatureType uint8          `eip712:"signatureType,uint8"`
+}
+
+// ProxyFactoryDomain, ClobAuthDomain and ExchangeDomain take the chain ID
+// and the contracts from the same network, so a signature made for one
+// network doesn't verify on another. signTyped checks that the node is on
+// that network too.
+func ProxyFactoryDomain(network *web3.Network) web3.Domain {
+	return web3.Domain{
+		Name:              "Polymarket Contract Proxy Factory",
+		ChainID:           network.ChainID,
+		VerifyingContract: network.SafeFactory,
+	}
+}
+
+func ClobAuthDomain(network *web3.Network) web3.Domain {
+	return web3.Domain{
+		Name:    "ClobAuthDomain",
+		Version: "1",
+		ChainID: network.ChainID,
+	}
+}
+
+// ExchangeDomain is the domain of the exchange orders of plain or neg-risk
+// markets settle on.
+func ExchangeDomain(network *web3.Network, negRisk bool) web3.Domain {
+	return web3.Domain{
+		Name:              "Polymarket CTF Exchange",
+		Version:           "1",
+		ChainID:           network.ChainID,
+		VerifyingContract: network.Exchange(negRisk),
+	}
+}
+
+// signTyped signs message in a domain of the wallet's network, once the
+// node is known to be on it.
+func signTyped(wallet *web3.Wallet, domain web3.Domain, primaryType string, message any) (string, error) {
+	if err := wallet.CheckNetwork(context.Background()); err != nil {
+		return "", err
+	}
+
+	return wallet.SignTyped(context.Background(), domain, primaryType, message)
+}
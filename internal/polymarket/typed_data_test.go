@@ -0,0 +1,86 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"polymarket/internal/web3"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// createProxyJSON is the CreateProxy typed data account creation used to
+// sign as hand-written JSON, before the struct builder.
+const createProxyJSON = `{
+	"types": {
+		"CreateProxy": [
+			{"name": "paymentToken", "type": "address"},
+			{"name": "payment", "type": "uint256"},
+			{"name": "paymentReceiver", "type": "address"}
+		],
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		]
+	},
+	"primaryType": "CreateProxy",
+	"domain": {
+		"name": "Polymarket Contract Proxy Factory",
+		"chainId": "137",
+		"verifyingContract": "0xaacfeea03eb1561c4e67d661e40682bd20e3541b"
+	},
+	"message": {
+		"paymentToken": "0x0000000000000000000000000000000000000000",
+		"payment": "0",
+		"paymentReceiver": "0x0000000000000000000000000000000000000000"
+	}
+}`
+
+func TestCreateProxyDigest(t *testing.T) {
+	var legacy apitypes.TypedData
+	if err := json.Unmarshal([]byte(createProxyJSON), &legacy); err != nil {
+		t.Fatal(err)
+	}
+	want, err := web3.TypedDataHash(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := web3.NewTypedData(ProxyFactoryDomain(web3.Polygon), "CreateProxy", CreateProxy{Payment: big.NewInt(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := web3.TypedDataHash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != want {
+		t.Errorf("CreateProxy digest %s, want %s as signed from JSON", got, want)
+	}
+}
+
+func TestSignTypedChecksNetwork(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A Polygon wallet on an Amoy node must not sign for either chain.
+	wallet := web3.NewWithBackend(chainBackend{chainID: 80002}, web3.NewKeySigner(key))
+
+	if _, err := signTyped(wallet, ClobAuthDomain(wallet.Network), "ClobAuth", ClobAuth{Address: wallet.Address, Nonce: big.NewInt(0)}); err == nil {
+		t.Error("signed for polygon on a node of chain 80002")
+	}
+
+	clob := amoyClob(t)
+	for _, negRisk := range []bool{false, true} {
+		domain := ExchangeDomain(clob.Wallet.Network, negRisk)
+		if domain.ChainID.Int64() != 80002 || domain.VerifyingContract != clob.Wallet.Network.Exchange(negRisk) {
+			t.Errorf("neg risk %v: domain %+v mixes networks", negRisk, domain)
+		}
+	}
+}
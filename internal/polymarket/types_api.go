@@ -161,3 +161,59 @@ type RespTxCheck []struct {
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
 }
+
+type RespRelayerNonce struct {
+	Nonce string `json:"nonce"`
+}
+
+type SafeSignatureParams struct {
+	GasPrice       string `json:"gasPrice"`
+	Operation      string `json:"operation"`
+	SafeTxGas      string `json:"safeTxnGas"`
+	BaseGas        string `json:"baseGas"`
+	GasToken       string `json:"gasToken"`
+	RefundReceiver string `json:"refundReceiver"`
+}
+
+type PayloadSafeTx struct {
+	From            string              `json:"from"`
+	To              string              `json:"to"`
+	ProxyWallet     string              `json:"proxyWallet"`
+	Data            string              `json:"data"`
+	Nonce           string              `json:"nonce"`
+	Signature       string              `json:"signature"`
+	SignatureParams SafeSignatureParams `json:"signatureParams"`
+	Type            string              `json:"type"`
+	Metadata        string              `json:"metadata"`
+}
+
+type DataPosition struct {
+	ProxyWallet  string `json:"proxyWallet"`
+	Asset        string `json:"asset"`
+	ConditionID  string `json:"conditionId"`
+	Redeemable   bool   `json:"redeemable"`
+	Mergeable    bool   `json:"mergeable"`
+	Title        string `json:"title"`
+	Outcome      string `json:"outcome"`
+	OutcomeIndex int    `json:"outcomeIndex"`
+	NegativeRisk bool   `json:"negativeRisk"`
+}
+
+type MarketToken struct {
+	TokenID string  `json:"token_id"`
+	Outcome string  `json:"outcome"`
+	Price   float64 `json:"price"`
+	Winner  bool    `json:"winner"`
+}
+
+type Market struct {
+	ConditionID     string        `json:"condition_id"`
+	QuestionID      string        `json:"question_id"`
+	Question        string        `json:"question"`
+	MarketSlug      string        `json:"market_slug"`
+	Active          bool          `json:"active"`
+	Closed          bool          `json:"closed"`
+	NegRisk         bool          `json:"neg_risk"`
+	NegRiskMarketID string        `json:"neg_risk_market_id"`
+	Tokens          []MarketToken `json:"tokens"`
+}
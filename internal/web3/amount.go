@@ -0,0 +1,94 @@
+package web3
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// USDCDecimals is the precision of USDC.e, Polymarket's collateral.
+const USDCDecimals = 6
+
+// Amount is an exact token amount: Raw base units of a token with Decimals.
+type Amount struct {
+	Raw      *big.Int
+	Decimals uint8
+}
+
+func NewAmount(raw *big.Int, decimals uint8) Amount {
+	if raw == nil {
+		raw = new(big.Int)
+	}
+
+	return Amount{Raw: raw, Decimals: decimals}
+}
+
+// ParseAmount parses a decimal string like "12.5" without rounding; more
+// fractional digits than decimals is an error. At least one digit is
+// required, signs and exponents are not accepted.
+func ParseAmount(s string, decimals uint8) (Amount, error) {
+	s = strings.TrimSpace(s)
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole+frac == "" || strings.TrimLeft(whole+frac, "0123456789") != "" {
+		return Amount{}, fmt.Errorf("invalid amount %q", s)
+	}
+	if len(frac) > int(decimals) {
+		return Amount{}, fmt.Errorf("amount %s has more than %d decimals", s, decimals)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	raw, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", int(decimals)-len(frac)), 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid amount %q", s)
+	}
+
+	return Amount{Raw: raw, Decimals: decimals}, nil
+}
+
+func ParseUSDC(s string) (Amount, error) {
+	return ParseAmount(s, USDCDecimals)
+}
+
+// String formats the amount with trailing fractional zeros removed.
+func (a Amount) String() string {
+	raw := a.raw()
+
+	digits := new(big.Int).Abs(raw).String()
+	if len(digits) <= int(a.Decimals) {
+		digits = strings.Repeat("0", int(a.Decimals)-len(digits)+1) + digits
+	}
+
+	whole := digits[:len(digits)-int(a.Decimals)]
+	frac := strings.TrimRight(digits[len(digits)-int(a.Decimals):], "0")
+
+	sign := ""
+	if raw.Sign() < 0 {
+		sign = "-"
+	}
+
+	if frac == "" {
+		return sign + whole
+	}
+
+	return sign + whole + "." + frac
+}
+
+func (a Amount) IsZero() bool {
+	return a.raw().Sign() == 0
+}
+
+// Cmp compares the raw values of two amounts of the same token.
+func (a Amount) Cmp(b Amount) int {
+	return a.raw().Cmp(b.raw())
+}
+
+func (a Amount) raw() *big.Int {
+	if a.Raw == nil {
+		return new(big.Int)
+	}
+
+	return a.Raw
+}
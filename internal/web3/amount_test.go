@@ -0,0 +1,34 @@
+package web3
+
+import "testing"
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"12.5", "12500000"},
+		{" 1 ", "1000000"},
+		{".5", "500000"},
+		{"5.", "5000000"},
+		{"0.000001", "1"},
+		{"007", "7000000"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUSDC(tt.in)
+		if err != nil {
+			t.Errorf("ParseUSDC(%q): %v", tt.in, err)
+			continue
+		}
+		if got.Raw.String() != tt.want {
+			t.Errorf("ParseUSDC(%q) = %s, want %s", tt.in, got.Raw, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", " ", ".", "-1", "+1", "1e6", "1.2.3", "0.0000001", "1_000", "0x10"} {
+		if got, err := ParseUSDC(in); err == nil {
+			t.Errorf("ParseUSDC(%q) = %s, want error", in, got.Raw)
+		}
+	}
+}
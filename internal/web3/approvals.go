@@ -0,0 +1,176 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// minUnlimitedAllowance is where an allowance counts as unlimited; spending
+// from a MaxUint256 approval lowers it, it must still count as approved.
+var minUnlimitedAllowance = new(big.Int).Rsh(math.MaxBig256, 1)
+
+// ApprovalKind tells which token an approval covers.
+type ApprovalKind string
+
+const (
+	ApprovalUSDC ApprovalKind = "USDC.e allowance"
+	ApprovalCTF  ApprovalKind = "CTF approval for all"
+)
+
+// ApprovalStatus is one approval the proxy needs to trade. Granted is set
+// for the ones submitted by the current setup.
+type ApprovalStatus struct {
+	Kind     ApprovalKind
+	Contract string
+	Spender  common.Address
+	Approved bool
+	Granted  bool
+}
+
+func (s ApprovalStatus) String() string {
+	state := "missing"
+	switch {
+	case s.Granted:
+		state = "granted"
+	case s.Approved:
+		state = "already approved"
+	}
+
+	return fmt.Sprintf("%s to %s (%s): %s", s.Kind, s.Contract, s.Spender, state)
+}
+
+// tradingSpenders are the contracts moving the proxy's collateral and
+// outcome tokens when it trades.
+func (n *Network) tradingSpenders() []struct {
+	name    string
+	address common.Address
+} {
+	return []struct {
+		name    string
+		address common.Address
+	}{
+		{"CTF Exchange", n.CTFExchange},
+		{"Neg Risk Exchange", n.NegRiskExchange},
+		{"Neg Risk Adapter", n.NegRiskAdapter},
+	}
+}
+
+// Approvals reads which trading approvals the Safe proxy already has.
+func (s *Safe) Approvals(ctx context.Context) ([]ApprovalStatus, error) {
+	usdc := s.wallet.USDC()
+	ctf := s.wallet.CTF()
+
+	var statuses []ApprovalStatus
+
+	for _, spender := range s.wallet.Network.tradingSpenders() {
+		allowance, err := usdc.Allowance(ctx, s.Address, spender.address)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, ApprovalStatus{
+			Kind:     ApprovalUSDC,
+			Contract: spender.name,
+			Spender:  spender.address,
+			Approved: allowance.Raw.Cmp(minUnlimitedAllowance) >= 0,
+		})
+	}
+
+	for _, spender := range s.wallet.Network.tradingSpenders() {
+		approved, err := ctf.IsApprovedForAll(ctx, s.Address, spender.address)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, ApprovalStatus{
+			Kind:     ApprovalCTF,
+			Contract: spender.name,
+			Spender:  spender.address,
+			Approved: approved,
+		})
+	}
+
+	return statuses, nil
+}
+
+// ApprovalCalls are the Safe calls granting every approval not yet given.
+func (s *Safe) ApprovalCalls(statuses []ApprovalStatus) ([]MultiSendCall, error) {
+	usdc := s.wallet.USDC()
+	ctf := s.wallet.CTF()
+
+	var calls []MultiSendCall
+
+	for _, status := range statuses {
+		if status.Approved {
+			continue
+		}
+
+		var (
+			call MultiSendCall
+			err  error
+		)
+
+		switch status.Kind {
+		case ApprovalUSDC:
+			call.To = usdc.Address
+			call.Data, err = usdc.ApproveData(status.Spender, NewAmount(math.MaxBig256, usdc.Decimals))
+		case ApprovalCTF:
+			call.To = ctf.Address
+			call.Data, err = ctf.SetApprovalForAllData(status.Spender, true)
+		default:
+			err = fmt.Errorf("unknown approval %q", status.Kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
+
+// SetupApprovals grants the missing trading approvals in one Safe batch
+// paid by the wallet's EOA. Nothing is sent when all are in place.
+func (s *Safe) SetupApprovals(ctx context.Context) ([]ApprovalStatus, error) {
+	statuses, err := s.Approvals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calls, err := s.ApprovalCalls(statuses)
+	if err != nil {
+		return nil, err
+	}
+	if len(calls) == 0 {
+		return statuses, nil
+	}
+
+	pending, err := s.ExecuteBatch(ctx, calls)
+	if err != nil {
+		return statuses, err
+	}
+
+	if _, err := pending.Wait(ctx, 1); err != nil {
+		return statuses, err
+	}
+
+	return MarkGranted(statuses), nil
+}
+
+// MarkGranted marks the missing approvals as granted once their batch is
+// mined.
+func MarkGranted(statuses []ApprovalStatus) []ApprovalStatus {
+	for i := range statuses {
+		if !statuses[i].Approved {
+			statuses[i].Approved = true
+			statuses[i].Granted = true
+		}
+	}
+
+	return statuses
+}
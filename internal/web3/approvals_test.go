@@ -0,0 +1,148 @@
+package web3
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// countingSends counts the transactions sent through it.
+type countingSends struct {
+	Backend
+	sent int
+}
+
+func (c *countingSends) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.sent++
+
+	return c.Backend.SendTransaction(ctx, tx)
+}
+
+// newApprovalsSafe is the Safe of a stub wallet whose USDC.e allowances
+// and CTF approvals to the trading spenders are the given ones, in the
+// order of Network.tradingSpenders.
+func newApprovalsSafe(t *testing.T, allowances []*big.Int, approved []bool) (*Safe, *countingSends) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	safe, err := Polygon.ProxyAddress(ProxySafe, crypto.PubkeyToAddress(key.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usdc := newStubContract(t, erc20ABI)
+	ctf := newStubContract(t, ctfABI)
+	for i, spender := range Polygon.tradingSpenders() {
+		usdc.answer("allowance", []any{safe, spender.address}, allowances[i])
+		ctf.answer("isApprovedForAll", []any{safe, spender.address}, approved[i])
+	}
+
+	wallet := newStubWalletWithKey(t, key, map[common.Address]*stubContract{
+		Polygon.USDC:              usdc,
+		Polygon.ConditionalTokens: ctf,
+	})
+	client := &countingSends{Backend: wallet.Client}
+	wallet.Client = client
+
+	s, err := wallet.Safe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address != safe {
+		t.Fatalf("safe %s, want %s", s.Address, safe)
+	}
+
+	return s, client
+}
+
+func TestApprovalsDetected(t *testing.T) {
+	ctx := context.Background()
+
+	// Spending from a MaxUint256 allowance keeps it approved, a finite
+	// one is not.
+	spent := new(big.Int).Sub(math.MaxBig256, big.NewInt(1_000_000_000))
+	safe, _ := newApprovalsSafe(t,
+		[]*big.Int{math.MaxBig256, spent, big.NewInt(1_000_000_000_000)},
+		[]bool{true, false, true})
+
+	statuses, err := safe.Approvals(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		kind     ApprovalKind
+		spender  common.Address
+		approved bool
+	}{
+		{ApprovalUSDC, Polygon.CTFExchange, true},
+		{ApprovalUSDC, Polygon.NegRiskExchange, true},
+		{ApprovalUSDC, Polygon.NegRiskAdapter, false},
+		{ApprovalCTF, Polygon.CTFExchange, true},
+		{ApprovalCTF, Polygon.NegRiskExchange, false},
+		{ApprovalCTF, Polygon.NegRiskAdapter, true},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("%d statuses, want %d", len(statuses), len(want))
+	}
+	for i, w := range want {
+		got := statuses[i]
+		if got.Kind != w.kind || got.Spender != w.spender || got.Approved != w.approved || got.Granted {
+			t.Errorf("status %d: %s, want %s to %s approved %v", i, got, w.kind, w.spender, w.approved)
+		}
+	}
+
+	calls, err := safe.ApprovalCalls(statuses)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usdc := safe.wallet.USDC()
+	approve, err := usdc.ApproveData(Polygon.NegRiskAdapter, NewAmount(math.MaxBig256, usdc.Decimals))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setApproval, err := safe.wallet.CTF().SetApprovalForAllData(Polygon.NegRiskExchange, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 2 ||
+		calls[0].To != Polygon.USDC || !bytes.Equal(calls[0].Data, approve) ||
+		calls[1].To != Polygon.ConditionalTokens || !bytes.Equal(calls[1].Data, setApproval) {
+		t.Errorf("calls %+v, want USDC.e approve of the adapter and CTF approval of the neg risk exchange", calls)
+	}
+}
+
+func TestSetupApprovalsIdempotent(t *testing.T) {
+	ctx := context.Background()
+
+	safe, client := newApprovalsSafe(t,
+		[]*big.Int{math.MaxBig256, math.MaxBig256, math.MaxBig256},
+		[]bool{true, true, true})
+
+	for run := 1; run <= 2; run++ {
+		statuses, err := safe.SetupApprovals(ctx)
+		if err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+		for _, status := range statuses {
+			if !status.Approved || status.Granted {
+				t.Errorf("run %d: %s", run, status)
+			}
+		}
+	}
+
+	if client.sent != 0 {
+		t.Errorf("%d txs sent with every approval in place", client.sent)
+	}
+}
@@ -0,0 +1,51 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Backend is everything Wallet needs from a node. *ethclient.Client,
+// *MultiClient and the simulated backend's client all implement it.
+type Backend interface {
+	bind.ContractBackend
+	ethereum.ChainIDReader
+	ethereum.BlockNumberReader
+	ethereum.FeeHistoryReader
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// Dial connects to rpc, a single URL or a comma separated list of them. A
+// list gets a MultiClient failing over between the endpoints.
+func Dial(rpc string) (Backend, error) {
+	var urls []string
+	for _, url := range strings.Split(rpc, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	switch len(urls) {
+	case 0:
+		return nil, errors.New("no RPC url given")
+	case 1:
+		client, err := ethclient.Dial(urls[0])
+		if err != nil {
+			return nil, fmt.Errorf("can't connect to RPC: %w", err)
+		}
+		return client, nil
+	default:
+		return NewMultiClient(context.Background(), urls)
+	}
+}
@@ -0,0 +1,50 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func mustABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("bad contract ABI: %v", err))
+	}
+
+	return parsed
+}
+
+// call runs a view method of contract and unpacks its outputs.
+func (w *Wallet) call(ctx context.Context, contractABI abi.ABI, contract common.Address, method string, args ...any) ([]any, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	res, err := w.Client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error when call %s on %s: %w", method, contract, err)
+	}
+
+	out, err := contractABI.Unpack(method, res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s: %w", method, err)
+	}
+
+	return out, nil
+}
+
+// transact sends a call of method on contract from the wallet.
+func (w *Wallet) transact(ctx context.Context, contractABI abi.ABI, contract common.Address, method string, args ...any) (*PendingTx, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	return w.SendTx(ctx, TxRequest{To: &contract, Data: data})
+}
@@ -0,0 +1,248 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ctfABI = mustABI(`[
+	{"name":"balanceOf","type":"function","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"balanceOfBatch","type":"function","stateMutability":"view","inputs":[{"name":"owners","type":"address[]"},{"name":"ids","type":"uint256[]"}],"outputs":[{"name":"","type":"uint256[]"}]},
+	{"name":"payoutDenominator","type":"function","stateMutability":"view","inputs":[{"name":"conditionId","type":"bytes32"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"payoutNumerators","type":"function","stateMutability":"view","inputs":[{"name":"conditionId","type":"bytes32"},{"name":"index","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"isApprovedForAll","type":"function","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"name":"setApprovalForAll","type":"function","stateMutability":"nonpayable","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[]},
+	{"name":"redeemPositions","type":"function","stateMutability":"nonpayable","inputs":[{"name":"collateralToken","type":"address"},{"name":"parentCollectionId","type":"bytes32"},{"name":"conditionId","type":"bytes32"},{"name":"indexSets","type":"uint256[]"}],"outputs":[]},
+	{"name":"splitPosition","type":"function","stateMutability":"nonpayable","inputs":[{"name":"collateralToken","type":"address"},{"name":"parentCollectionId","type":"bytes32"},{"name":"conditionId","type":"bytes32"},{"name":"partition","type":"uint256[]"},{"name":"amount","type":"uint256"}],"outputs":[]},
+	{"name":"mergePositions","type":"function","stateMutability":"nonpayable","inputs":[{"name":"collateralToken","type":"address"},{"name":"parentCollectionId","type":"bytes32"},{"name":"conditionId","type":"bytes32"},{"name":"partition","type":"uint256[]"},{"name":"amount","type":"uint256"}],"outputs":[]},
+	{"name":"getOutcomeSlotCount","type":"function","stateMutability":"view","inputs":[{"name":"conditionId","type":"bytes32"}],"outputs":[{"name":"","type":"uint256"}]}
+]`)
+
+// ConditionalTokens reads outcome token positions from the Gnosis
+// Conditional Tokens contract. Outcome tokens have the collateral's 6
+// decimals.
+type ConditionalTokens struct {
+	Address common.Address
+
+	wallet *Wallet
+}
+
+// Position is an outcome token: its ERC-1155 id and the condition and
+// outcome index set it was minted for. NegRisk positions are collateralized
+// by the Neg Risk Adapter's wrapped USDC and redeemed through the adapter.
+type Position struct {
+	ID          *big.Int
+	ConditionID common.Hash
+	IndexSet    *big.Int
+	NegRisk     bool
+}
+
+// PositionBalance is a held position. Payout is what redeeming it would
+// pay out now, zero until the condition is resolved.
+type PositionBalance struct {
+	Position
+	Balance    Amount
+	Resolved   bool
+	Redeemable bool
+	Payout     Amount
+}
+
+// Resolution is the reported outcome of a condition. Denominator is zero
+// while it is unresolved.
+type Resolution struct {
+	ConditionID common.Hash
+	Denominator *big.Int
+	Numerators  []*big.Int
+}
+
+func (r *Resolution) Resolved() bool {
+	return r.Denominator.Sign() > 0
+}
+
+// Payout is what amount of a position with indexSet redeems for. The index
+// set must be non-zero and name only outcomes of the condition once it is
+// resolved.
+func (r *Resolution) Payout(indexSet *big.Int, amount Amount) (Amount, error) {
+	if indexSet == nil || indexSet.Sign() <= 0 {
+		return Amount{}, fmt.Errorf("invalid index set %v for condition %s", indexSet, r.ConditionID)
+	}
+
+	if !r.Resolved() {
+		return NewAmount(nil, amount.Decimals), nil
+	}
+
+	if indexSet.BitLen() > len(r.Numerators) {
+		return Amount{}, fmt.Errorf("index set %s is out of %d outcomes of condition %s", indexSet, len(r.Numerators), r.ConditionID)
+	}
+
+	numerator := new(big.Int)
+	for i, n := range r.Numerators {
+		if indexSet.Bit(i) == 1 {
+			numerator.Add(numerator, n)
+		}
+	}
+
+	payout := new(big.Int).Mul(amount.raw(), numerator)
+
+	return NewAmount(payout.Div(payout, r.Denominator), amount.Decimals), nil
+}
+
+func (w *Wallet) CTF() *ConditionalTokens {
+	return &ConditionalTokens{
+		Address: w.Network.ConditionalTokens,
+		wallet:  w,
+	}
+}
+
+func (c *ConditionalTokens) BalanceOf(ctx context.Context, owner common.Address, positionID *big.Int) (Amount, error) {
+	out, err := c.wallet.call(ctx, ctfABI, c.Address, "balanceOf", owner, positionID)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	return NewAmount(out[0].(*big.Int), USDCDecimals), nil
+}
+
+func (c *ConditionalTokens) IsApprovedForAll(ctx context.Context, owner, operator common.Address) (bool, error) {
+	out, err := c.wallet.call(ctx, ctfABI, c.Address, "isApprovedForAll", owner, operator)
+	if err != nil {
+		return false, err
+	}
+
+	return out[0].(bool), nil
+}
+
+// SetApprovalForAllData is the calldata letting operator move all outcome
+// tokens, for calls made by the Safe proxy.
+func (c *ConditionalTokens) SetApprovalForAllData(operator common.Address, approved bool) ([]byte, error) {
+	return ctfABI.Pack("setApprovalForAll", operator, approved)
+}
+
+// BalanceOfBatch reads the balance of owner for every position id.
+func (c *ConditionalTokens) BalanceOfBatch(ctx context.Context, owner common.Address, positionIDs []*big.Int) ([]Amount, error) {
+	owners := make([]common.Address, len(positionIDs))
+	for i := range owners {
+		owners[i] = owner
+	}
+
+	out, err := c.wallet.call(ctx, ctfABI, c.Address, "balanceOfBatch", owners, positionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := out[0].([]*big.Int)
+	if len(raw) != len(positionIDs) {
+		return nil, fmt.Errorf("balanceOfBatch returned %d balances for %d ids", len(raw), len(positionIDs))
+	}
+
+	balances := make([]Amount, len(raw))
+	for i, balance := range raw {
+		balances[i] = NewAmount(balance, USDCDecimals)
+	}
+
+	return balances, nil
+}
+
+// OutcomeSlotCount is the number of outcomes conditionID was prepared with,
+// zero if it was never prepared.
+func (c *ConditionalTokens) OutcomeSlotCount(ctx context.Context, conditionID common.Hash) (uint64, error) {
+	out, err := c.wallet.call(ctx, ctfABI, c.Address, "getOutcomeSlotCount", conditionID)
+	if err != nil {
+		return 0, err
+	}
+
+	slots := out[0].(*big.Int)
+	if !slots.IsUint64() {
+		return 0, fmt.Errorf("condition %s has %s outcomes", conditionID, slots)
+	}
+
+	return slots.Uint64(), nil
+}
+
+// Resolution reads the payout vector of a condition.
+func (c *ConditionalTokens) Resolution(ctx context.Context, conditionID common.Hash) (*Resolution, error) {
+	out, err := c.wallet.call(ctx, ctfABI, c.Address, "payoutDenominator", conditionID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolution := &Resolution{
+		ConditionID: conditionID,
+		Denominator: out[0].(*big.Int),
+	}
+
+	if !resolution.Resolved() {
+		return resolution, nil
+	}
+
+	slots, err := c.OutcomeSlotCount(ctx, conditionID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := uint64(0); i < slots; i++ {
+		out, err = c.wallet.call(ctx, ctfABI, c.Address, "payoutNumerators", conditionID, new(big.Int).SetUint64(i))
+		if err != nil {
+			return nil, err
+		}
+		resolution.Numerators = append(resolution.Numerators, out[0].(*big.Int))
+	}
+
+	return resolution, nil
+}
+
+// Positions reads owner's balance of every position and whether it can be
+// redeemed for a non-zero payout.
+func (c *ConditionalTokens) Positions(ctx context.Context, owner common.Address, positions []Position) ([]PositionBalance, error) {
+	ids := make([]*big.Int, len(positions))
+	for i, position := range positions {
+		ids[i] = position.ID
+	}
+
+	balances, err := c.BalanceOfBatch(ctx, owner, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resolutions := make(map[common.Hash]*Resolution)
+	result := make([]PositionBalance, len(positions))
+
+	for i, position := range positions {
+		resolution, ok := resolutions[position.ConditionID]
+		if !ok {
+			resolution, err = c.Resolution(ctx, position.ConditionID)
+			if err != nil {
+				return nil, err
+			}
+			resolutions[position.ConditionID] = resolution
+		}
+
+		payout, err := resolution.Payout(position.IndexSet, balances[i])
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = PositionBalance{
+			Position:   position,
+			Balance:    balances[i],
+			Resolved:   resolution.Resolved(),
+			Redeemable: !balances[i].IsZero() && !payout.IsZero(),
+			Payout:     payout,
+		}
+	}
+
+	return result, nil
+}
+
+// ProxyPositions is Positions for the wallet's Safe proxy.
+func (c *ConditionalTokens) ProxyPositions(ctx context.Context, positions []Position) ([]PositionBalance, error) {
+	proxyAddress, err := c.wallet.CreateProxyAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Positions(ctx, common.HexToAddress(proxyAddress), positions)
+}
@@ -0,0 +1,181 @@
+package web3
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// stubCode answers every call with bytes stored for its calldata: with
+// key = keccak256(calldata), slot key holds the length of the answer and
+// slots key+1... its 32 byte words. Unknown calldata returns nothing.
+var stubCode = common.FromHex("0x" +
+	"3660006000373660002080546000" + // copy calldata, key, length, i = 0
+	"5b81811015602857" + // while i < length
+	"8060051c8301600101548152602001600e56" + // mem[i] = slot[key+1+i/32], i += 32
+	"5b506000f3") // return mem[:length]
+
+// stubContract collects the answers of a stub contract.
+type stubContract struct {
+	t       *testing.T
+	abi     abi.ABI
+	storage map[common.Hash]common.Hash
+}
+
+func newStubContract(t *testing.T, contractABI abi.ABI) *stubContract {
+	return &stubContract{t: t, abi: contractABI, storage: make(map[common.Hash]common.Hash)}
+}
+
+// answer makes a call of method with args return outputs.
+func (s *stubContract) answer(method string, args []any, outputs ...any) {
+	s.t.Helper()
+
+	calldata, err := s.abi.Pack(method, args...)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	ret, err := s.abi.Methods[method].Outputs.Pack(outputs...)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	key := new(big.Int).SetBytes(crypto.Keccak256(calldata))
+	s.storage[common.BigToHash(key)] = common.BigToHash(big.NewInt(int64(len(ret))))
+
+	for i := 0; i < len(ret); i += 32 {
+		slot := new(big.Int).Add(key, big.NewInt(int64(1+i/32)))
+		s.storage[common.BigToHash(slot)] = common.BytesToHash(ret[i : i+32])
+	}
+}
+
+func newStubWallet(t *testing.T, contracts map[common.Address]*stubContract) *Wallet {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newStubWalletWithKey(t, key, contracts)
+}
+
+// newStubWalletWithKey is newStubWallet for a given key, for contracts
+// whose address depends on the owner.
+func newStubWalletWithKey(t *testing.T, key *ecdsa.PrivateKey, contracts map[common.Address]*stubContract) *Wallet {
+	t.Helper()
+
+	alloc := make(types.GenesisAlloc)
+	for address, contract := range contracts {
+		alloc[address] = types.Account{Code: stubCode, Balance: new(big.Int), Storage: contract.storage}
+	}
+
+	backend := simulated.NewBackend(alloc)
+	t.Cleanup(func() { backend.Close() })
+
+	return NewWithBackend(backend.Client(), NewKeySigner(key))
+}
+
+func TestCTFReads(t *testing.T) {
+	ctx := context.Background()
+
+	owner := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	resolved := common.HexToHash("0x01")
+	open := common.HexToHash("0x02")
+	yes, no, other := big.NewInt(11), big.NewInt(12), big.NewInt(21)
+
+	ctf := newStubContract(t, ctfABI)
+	ctf.answer("balanceOf", []any{owner, yes}, big.NewInt(2_500_000))
+	ctf.answer("balanceOfBatch",
+		[]any{[]common.Address{owner, owner, owner}, []*big.Int{yes, no, other}},
+		[]*big.Int{big.NewInt(2_500_000), big.NewInt(0), big.NewInt(1_000_000)})
+	ctf.answer("payoutDenominator", []any{resolved}, big.NewInt(1))
+	ctf.answer("getOutcomeSlotCount", []any{resolved}, big.NewInt(2))
+	ctf.answer("payoutNumerators", []any{resolved, big.NewInt(0)}, big.NewInt(1))
+	ctf.answer("payoutNumerators", []any{resolved, big.NewInt(1)}, big.NewInt(0))
+	ctf.answer("payoutDenominator", []any{open}, big.NewInt(0))
+
+	wallet := newStubWallet(t, map[common.Address]*stubContract{Polygon.ConditionalTokens: ctf})
+	tokens := wallet.CTF()
+
+	balance, err := tokens.BalanceOf(ctx, owner, yes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.String() != "2.5" {
+		t.Errorf("balance %s, want 2.5", balance)
+	}
+
+	resolution, err := tokens.Resolution(ctx, resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resolution.Resolved() || len(resolution.Numerators) != 2 {
+		t.Fatalf("resolution %+v, want resolved with 2 outcomes", resolution)
+	}
+
+	balances, err := tokens.Positions(ctx, owner, []Position{
+		{ID: yes, ConditionID: resolved, IndexSet: big.NewInt(1)},
+		{ID: no, ConditionID: resolved, IndexSet: big.NewInt(2)},
+		{ID: other, ConditionID: open, IndexSet: big.NewInt(1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		balance    string
+		resolved   bool
+		redeemable bool
+		payout     string
+	}{
+		{"2.5", true, true, "2.5"},
+		{"0", true, false, "0"},
+		{"1", false, false, "0"},
+	}
+	for i, w := range want {
+		got := balances[i]
+		if got.Balance.String() != w.balance || got.Resolved != w.resolved || got.Redeemable != w.redeemable || got.Payout.String() != w.payout {
+			t.Errorf("position %s: balance %s resolved %t redeemable %t payout %s, want %s %t %t %s",
+				got.ID, got.Balance, got.Resolved, got.Redeemable, got.Payout, w.balance, w.resolved, w.redeemable, w.payout)
+		}
+	}
+
+	if _, err := tokens.BalanceOf(ctx, owner, big.NewInt(99)); err == nil {
+		t.Error("unanswered call succeeded")
+	}
+}
+
+func TestResolutionPayout(t *testing.T) {
+	resolution := &Resolution{
+		Denominator: big.NewInt(2),
+		Numerators:  []*big.Int{big.NewInt(1), big.NewInt(1)},
+	}
+	amount := NewAmount(big.NewInt(3_000_000), USDCDecimals)
+
+	payout, err := resolution.Payout(big.NewInt(1), amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payout.String() != "1.5" {
+		t.Errorf("payout %s, want 1.5", payout)
+	}
+
+	for _, indexSet := range []*big.Int{nil, big.NewInt(0), big.NewInt(-1), big.NewInt(4)} {
+		if _, err := resolution.Payout(indexSet, amount); err == nil {
+			t.Errorf("index set %v was accepted", indexSet)
+		}
+	}
+
+	unresolved := &Resolution{Denominator: new(big.Int)}
+	if _, err := unresolved.Payout(nil, amount); err == nil {
+		t.Error("nil index set was accepted before resolution")
+	}
+}
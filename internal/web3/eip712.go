@@ -0,0 +1,232 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Domain is an EIP-712 domain. Empty fields are left out of the
+// EIP712Domain type, as contracts differ in which ones they use.
+type Domain struct {
+	Name              string
+	Version           string
+	ChainID           *big.Int
+	VerifyingContract common.Address
+}
+
+func (d Domain) types() []apitypes.Type {
+	var types []apitypes.Type
+	if d.Name != "" {
+		types = append(types, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if d.Version != "" {
+		types = append(types, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if d.ChainID != nil {
+		types = append(types, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if d.VerifyingContract != (common.Address{}) {
+		types = append(types, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+
+	return types
+}
+
+func (d Domain) typedDataDomain() apitypes.TypedDataDomain {
+	domain := apitypes.TypedDataDomain{
+		Name:    d.Name,
+		Version: d.Version,
+	}
+	if d.ChainID != nil {
+		domain.ChainId = (*math.HexOrDecimal256)(d.ChainID)
+	}
+	if d.VerifyingContract != (common.Address{}) {
+		domain.VerifyingContract = strings.ToLower(d.VerifyingContract.Hex())
+	}
+
+	return domain
+}
+
+// typeCache holds the EIP-712 field list of every message struct seen.
+var typeCache sync.Map
+
+// domainSeparators caches domain separators by domain.
+var domainSeparators sync.Map
+
+// NewTypedData builds typed data from a message struct. Every field to sign
+// carries an `eip712:"name,type"` tag and fields are encoded in struct
+// order. Supported Go types are common.Address, common.Hash, *big.Int,
+// unsigned and signed integers, string, bool and []byte.
+func NewTypedData(domain Domain, primaryType string, message any) (apitypes.TypedData, error) {
+	v := reflect.Indirect(reflect.ValueOf(message))
+	if v.Kind() != reflect.Struct {
+		return apitypes.TypedData{}, fmt.Errorf("typed message must be a struct, got %T", message)
+	}
+
+	fields, err := messageFields(v.Type())
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+
+	msg := make(apitypes.TypedDataMessage, len(fields))
+	types := make([]apitypes.Type, 0, len(fields))
+
+	for _, f := range fields {
+		value, err := encodeValue(v.Field(f.index))
+		if err != nil {
+			return apitypes.TypedData{}, fmt.Errorf("field %s: %w", f.name, err)
+		}
+
+		msg[f.name] = value
+		types = append(types, apitypes.Type{Name: f.name, Type: f.typ})
+	}
+
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": domain.types(),
+			primaryType:    types,
+		},
+		PrimaryType: primaryType,
+		Domain:      domain.typedDataDomain(),
+		Message:     msg,
+	}, nil
+}
+
+type messageField struct {
+	index int
+	name  string
+	typ   string
+}
+
+func messageFields(t reflect.Type) ([]messageField, error) {
+	if cached, ok := typeCache.Load(t); ok {
+		return cached.([]messageField), nil
+	}
+
+	var fields []messageField
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("eip712")
+		if !ok {
+			continue
+		}
+
+		name, typ, ok := strings.Cut(tag, ",")
+		if !ok || name == "" || typ == "" {
+			return nil, fmt.Errorf("bad eip712 tag %q on %s.%s", tag, t.Name(), t.Field(i).Name)
+		}
+
+		fields = append(fields, messageField{index: i, name: name, typ: typ})
+	}
+
+	typeCache.Store(t, fields)
+
+	return fields, nil
+}
+
+// encodeValue turns a field into the form apitypes expects. Integers become
+// decimal strings so they survive a JSON trip to a remote signer intact.
+func encodeValue(v reflect.Value) (any, error) {
+	switch val := v.Interface().(type) {
+	case common.Address:
+		return val.Hex(), nil
+	case common.Hash:
+		return val.Hex(), nil
+	case *big.Int:
+		if val == nil {
+			return "0", nil
+		}
+		return val.String(), nil
+	case []byte:
+		return hexutil.Encode(val), nil
+	case string, bool:
+		return val, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint()).String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()).String(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// TypedDataHash is the EIP-712 digest that gets signed.
+func TypedDataHash(data apitypes.TypedData) (common.Hash, error) {
+	hash, err := typedDataHash(data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.BytesToHash(hash), nil
+}
+
+func typedDataHash(data apitypes.TypedData) ([]byte, error) {
+	domainSeparator, err := domainSeparator(data)
+	if err != nil {
+		return nil, err
+	}
+
+	typedDataHash, err := data.HashStruct(data.PrimaryType, data.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %v", err)
+	}
+
+	rawData := []byte(fmt.Sprintf("\x19\x01%s%s", string(domainSeparator), string(typedDataHash)))
+
+	return crypto.Keccak256(rawData), nil
+}
+
+func domainSeparator(data apitypes.TypedData) ([]byte, error) {
+	d := data.Domain
+	key := fmt.Sprintf("%s|%s|%v|%s|%s|%v", d.Name, d.Version, (*big.Int)(d.ChainId), strings.ToLower(d.VerifyingContract), d.Salt, data.Types["EIP712Domain"])
+
+	if cached, ok := domainSeparators.Load(key); ok {
+		return cached.([]byte), nil
+	}
+
+	separator, err := data.HashStruct("EIP712Domain", d.Map())
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %v", err)
+	}
+
+	domainSeparators.Store(key, []byte(separator))
+
+	return separator, nil
+}
+
+// SignTypedData signs data and checks the signature recovers to the wallet.
+func (w *Wallet) SignTypedData(ctx context.Context, data apitypes.TypedData) (string, error) {
+	signature, err := w.Signer.SignTypedData(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	if err := VerifyTypedData(w.Address, data, signature); err != nil {
+		return "", fmt.Errorf("signature does not verify: %w", err)
+	}
+
+	return hexutil.Encode(signature), nil
+}
+
+// SignTyped builds typed data from a message struct, see NewTypedData, and
+// signs it.
+func (w *Wallet) SignTyped(ctx context.Context, domain Domain, primaryType string, message any) (string, error) {
+	data, err := NewTypedData(domain, primaryType, message)
+	if err != nil {
+		return "", err
+	}
+
+	return w.SignTypedData(ctx, data)
+}
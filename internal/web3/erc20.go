@@ -0,0 +1,188 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var erc20ABI = mustABI(`[
+	{"name":"balanceOf","type":"function","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"allowance","type":"function","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"decimals","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"name":"symbol","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"name":"approve","type":"function","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"name":"transfer","type":"function","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"name":"Transfer","type":"event","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`)
+
+// ERC20 is a token read and moved by a Wallet.
+type ERC20 struct {
+	Address  common.Address
+	Symbol   string
+	Decimals uint8
+
+	wallet *Wallet
+}
+
+// TokenBalances are the balances of the wallet's EOA and its Safe proxy.
+type TokenBalances struct {
+	EOA   Amount
+	Proxy Amount
+}
+
+// USDC returns the network's USDC.e collateral token.
+func (w *Wallet) USDC() *ERC20 {
+	return &ERC20{
+		Address:  w.Network.USDC,
+		Symbol:   "USDC.e",
+		Decimals: USDCDecimals,
+		wallet:   w,
+	}
+}
+
+// Token loads symbol and decimals of the ERC-20 at address.
+func (w *Wallet) Token(ctx context.Context, address common.Address) (*ERC20, error) {
+	out, err := w.call(ctx, erc20ABI, address, "decimals")
+	if err != nil {
+		return nil, err
+	}
+	decimals := out[0].(uint8)
+
+	out, err = w.call(ctx, erc20ABI, address, "symbol")
+	if err != nil {
+		return nil, err
+	}
+	symbol := out[0].(string)
+
+	return &ERC20{
+		Address:  address,
+		Symbol:   symbol,
+		Decimals: decimals,
+		wallet:   w,
+	}, nil
+}
+
+// Parse parses a human amount of the token, e.g. "12.5".
+func (t *ERC20) Parse(s string) (Amount, error) {
+	return ParseAmount(s, t.Decimals)
+}
+
+func (t *ERC20) BalanceOf(ctx context.Context, owner common.Address) (Amount, error) {
+	out, err := t.wallet.call(ctx, erc20ABI, t.Address, "balanceOf", owner)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	return NewAmount(out[0].(*big.Int), t.Decimals), nil
+}
+
+// Balances reads the balance of the wallet's EOA and of its Safe proxy.
+func (t *ERC20) Balances(ctx context.Context) (*TokenBalances, error) {
+	eoa, err := t.BalanceOf(ctx, t.wallet.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyAddress, err := t.wallet.CreateProxyAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := t.BalanceOf(ctx, common.HexToAddress(proxyAddress))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenBalances{EOA: eoa, Proxy: proxy}, nil
+}
+
+func (t *ERC20) Allowance(ctx context.Context, owner, spender common.Address) (Amount, error) {
+	out, err := t.wallet.call(ctx, erc20ABI, t.Address, "allowance", owner, spender)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	return NewAmount(out[0].(*big.Int), t.Decimals), nil
+}
+
+// HasAllowance tells whether spender may move at least amount of owner's
+// tokens.
+func (t *ERC20) HasAllowance(ctx context.Context, owner, spender common.Address, amount Amount) (bool, error) {
+	allowance, err := t.Allowance(ctx, owner, spender)
+	if err != nil {
+		return false, err
+	}
+
+	return allowance.Cmp(amount) >= 0, nil
+}
+
+// Approve lets spender move amount of the wallet's tokens.
+func (t *ERC20) Approve(ctx context.Context, spender common.Address, amount Amount) (*PendingTx, error) {
+	if err := t.checkDecimals(amount); err != nil {
+		return nil, err
+	}
+
+	return t.wallet.transact(ctx, erc20ABI, t.Address, "approve", spender, amount.Raw)
+}
+
+// Transfer sends amount of the wallet's tokens to to.
+func (t *ERC20) Transfer(ctx context.Context, to common.Address, amount Amount) (*PendingTx, error) {
+	if err := t.checkDecimals(amount); err != nil {
+		return nil, err
+	}
+
+	return t.wallet.transact(ctx, erc20ABI, t.Address, "transfer", to, amount.Raw)
+}
+
+// ApproveData and TransferData are the calldata of approve and transfer, for
+// calls made by another account such as the Safe proxy.
+func (t *ERC20) ApproveData(spender common.Address, amount Amount) ([]byte, error) {
+	if err := t.checkDecimals(amount); err != nil {
+		return nil, err
+	}
+
+	return erc20ABI.Pack("approve", spender, amount.Raw)
+}
+
+func (t *ERC20) TransferData(to common.Address, amount Amount) ([]byte, error) {
+	if err := t.checkDecimals(amount); err != nil {
+		return nil, err
+	}
+
+	return erc20ABI.Pack("transfer", to, amount.Raw)
+}
+
+// ReceivedIn sums the tokens transferred to owner by the transaction of
+// receipt, read from its Transfer logs rather than from balances that other
+// transactions may move too.
+func (t *ERC20) ReceivedIn(receipt *types.Receipt, owner common.Address) Amount {
+	event := erc20ABI.Events["Transfer"]
+	received := new(big.Int)
+
+	for _, l := range receipt.Logs {
+		if l.Address != t.Address || len(l.Topics) != 3 || l.Topics[0] != event.ID {
+			continue
+		}
+		if common.BytesToAddress(l.Topics[2].Bytes()) != owner {
+			continue
+		}
+		received.Add(received, new(big.Int).SetBytes(l.Data))
+	}
+
+	return NewAmount(received, t.Decimals)
+}
+
+func (t *ERC20) checkDecimals(amount Amount) error {
+	if amount.Decimals != t.Decimals {
+		return fmt.Errorf("amount has %d decimals, %s has %d", amount.Decimals, t.Symbol, t.Decimals)
+	}
+	if amount.Raw == nil || amount.Raw.Sign() < 0 {
+		return fmt.Errorf("invalid amount %s", amount)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package web3
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestReceivedIn(t *testing.T) {
+	usdc := (&Wallet{Network: Polygon}).USDC()
+
+	proxy := common.HexToAddress("0x6d8c4e9aDF5748Af82Dabe2C6225207770d6B4fa")
+	other := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	transfer := erc20ABI.Events["Transfer"].ID
+
+	transferLog := func(token, from, to common.Address, units int64) *types.Log {
+		return &types.Log{
+			Address: token,
+			Topics:  []common.Hash{transfer, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:    common.BigToHash(big.NewInt(units)).Bytes(),
+		}
+	}
+
+	receipt := &types.Receipt{Logs: []*types.Log{
+		// Payouts of two redeemed conditions.
+		transferLog(Polygon.USDC, Polygon.ConditionalTokens, proxy, 2_000_000),
+		transferLog(Polygon.USDC, Polygon.NegRiskAdapter, proxy, 500_000),
+		// Not to the proxy, not USDC.e, not a Transfer.
+		transferLog(Polygon.USDC, proxy, other, 7_000_000),
+		transferLog(other, Polygon.ConditionalTokens, proxy, 9_000_000),
+		{Address: Polygon.USDC, Topics: []common.Hash{transfer, common.BytesToHash(proxy.Bytes())}, Data: common.BigToHash(big.NewInt(1)).Bytes()},
+		{Address: Polygon.USDC, Topics: []common.Hash{{0x01}, common.BytesToHash(other.Bytes()), common.BytesToHash(proxy.Bytes())}, Data: common.BigToHash(big.NewInt(1)).Bytes()},
+	}}
+
+	if received := usdc.ReceivedIn(receipt, proxy); received.String() != "2.5" {
+		t.Errorf("received %s, want 2.5", received)
+	}
+	if received := usdc.ReceivedIn(&types.Receipt{}, proxy); !received.IsZero() {
+		t.Errorf("received %s from no logs", received)
+	}
+}
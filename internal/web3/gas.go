@@ -0,0 +1,187 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// FeeReader is the part of the RPC client gas strategies price from.
+type FeeReader interface {
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+// Fees are the EIP-1559 prices for a transaction. BaseFee is the base fee
+// the strategy priced against.
+type Fees struct {
+	BaseFee      *big.Int
+	TipCap       *big.Int
+	MaxFeePerGas *big.Int
+}
+
+// GasStrategy decides what a transaction pays. The transaction sender asks
+// Wallet.Gas for fees every time it builds or bumps a transaction.
+type GasStrategy interface {
+	Fees(ctx context.Context, client FeeReader) (*Fees, error)
+}
+
+// SuggestedGas pays the node's suggested tip on top of the latest base fee.
+type SuggestedGas struct{}
+
+func (SuggestedGas) Fees(ctx context.Context, client FeeReader) (*Fees, error) {
+	tipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting gas tip: %w", err)
+	}
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting block header: %w", err)
+	}
+
+	return &Fees{
+		BaseFee:      header.BaseFee,
+		TipCap:       tipCap,
+		MaxFeePerGas: new(big.Int).Add(header.BaseFee, tipCap),
+	}, nil
+}
+
+// FeeHistoryGas takes the tip as the median of the Percentile-th reward of
+// the last Blocks blocks, and prices against the next block's base fee.
+type FeeHistoryGas struct {
+	Blocks     uint64
+	Percentile float64
+}
+
+func (s FeeHistoryGas) Fees(ctx context.Context, client FeeReader) (*Fees, error) {
+	blocks := s.Blocks
+	if blocks == 0 {
+		blocks = 20
+	}
+
+	history, err := client.FeeHistory(ctx, blocks, nil, []float64{s.Percentile})
+	if err != nil {
+		return nil, fmt.Errorf("error getting fee history: %w", err)
+	}
+
+	if len(history.BaseFee) == 0 {
+		return nil, errors.New("empty fee history")
+	}
+
+	var rewards []*big.Int
+	for _, reward := range history.Reward {
+		if len(reward) > 0 && reward[0] != nil {
+			rewards = append(rewards, reward[0])
+		}
+	}
+
+	tipCap := new(big.Int)
+	if len(rewards) > 0 {
+		sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+		tipCap.Set(rewards[len(rewards)/2])
+	}
+
+	// The last entry is the base fee of the next block.
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	return &Fees{
+		BaseFee:      baseFee,
+		TipCap:       tipCap,
+		MaxFeePerGas: new(big.Int).Add(baseFee, tipCap),
+	}, nil
+}
+
+// HeadroomGas keeps the tip of Strategy but lets the max fee absorb a base
+// fee of up to Multiplier times the current one, so the tx survives a spike.
+type HeadroomGas struct {
+	Strategy   GasStrategy
+	Multiplier float64
+}
+
+func (s HeadroomGas) Fees(ctx context.Context, client FeeReader) (*Fees, error) {
+	fees, err := s.Strategy.Fees(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	maxFeePerGas := new(big.Int).Add(mulFloat(fees.BaseFee, s.Multiplier), fees.TipCap)
+	if maxFeePerGas.Cmp(fees.MaxFeePerGas) > 0 {
+		fees.MaxFeePerGas = maxFeePerGas
+	}
+
+	return fees, nil
+}
+
+// CappedGas limits what Strategy may pay. Zero caps are ignored.
+type CappedGas struct {
+	Strategy   GasStrategy
+	MaxTipGwei float64
+	MaxFeeGwei float64
+}
+
+func (s CappedGas) Fees(ctx context.Context, client FeeReader) (*Fees, error) {
+	fees, err := s.Strategy.Fees(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Cap(fees)
+
+	return fees, nil
+}
+
+// Cap lowers fees to the configured caps in place.
+func (s CappedGas) Cap(fees *Fees) {
+	if s.MaxFeeGwei > 0 {
+		if maxFee := Gwei(s.MaxFeeGwei); fees.MaxFeePerGas.Cmp(maxFee) > 0 {
+			fees.MaxFeePerGas = maxFee
+		}
+	}
+
+	if s.MaxTipGwei > 0 {
+		if maxTip := Gwei(s.MaxTipGwei); fees.TipCap.Cmp(maxTip) > 0 {
+			fees.TipCap = maxTip
+		}
+	}
+
+	if fees.TipCap.Cmp(fees.MaxFeePerGas) > 0 {
+		fees.TipCap = new(big.Int).Set(fees.MaxFeePerGas)
+	}
+}
+
+// FixedGas is a user override, it always pays the given prices in gwei.
+type FixedGas struct {
+	TipGwei    float64
+	MaxFeeGwei float64
+}
+
+func (s FixedGas) Fees(ctx context.Context, client FeeReader) (*Fees, error) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting block header: %w", err)
+	}
+
+	return &Fees{
+		BaseFee:      header.BaseFee,
+		TipCap:       Gwei(s.TipGwei),
+		MaxFeePerGas: Gwei(s.MaxFeeGwei),
+	}, nil
+}
+
+func Gwei(gwei float64) *big.Int {
+	return mulFloat(big.NewInt(params.GWei), gwei)
+}
+
+func mulFloat(x *big.Int, f float64) *big.Int {
+	res, _ := new(big.Float).Mul(new(big.Float).SetInt(x), big.NewFloat(f)).Int(nil)
+
+	return res
+}
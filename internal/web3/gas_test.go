@@ -0,0 +1,105 @@
+package web3
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// stubFees answers a FeeReader with fixed prices, in gwei.
+type stubFees struct {
+	tip     int64
+	baseFee int64
+	history *ethereum.FeeHistory
+}
+
+func (s stubFees) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return Gwei(float64(s.tip)), nil
+}
+
+func (s stubFees) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: Gwei(float64(s.baseFee))}, nil
+}
+
+func (s stubFees) FeeHistory(_ context.Context, blocks uint64, _ *big.Int, _ []float64) (*ethereum.FeeHistory, error) {
+	return s.history, nil
+}
+
+func gweiHistory(baseFees []int64, rewards ...int64) *ethereum.FeeHistory {
+	history := new(ethereum.FeeHistory)
+	for _, fee := range baseFees {
+		history.BaseFee = append(history.BaseFee, Gwei(float64(fee)))
+	}
+	for _, reward := range rewards {
+		history.Reward = append(history.Reward, []*big.Int{Gwei(float64(reward))})
+	}
+
+	return history
+}
+
+func TestGasStrategies(t *testing.T) {
+	client := stubFees{
+		tip:     30,
+		baseFee: 100,
+		// The last base fee is the next block's.
+		history: gweiHistory([]int64{90, 100, 120}, 40, 25, 35),
+	}
+
+	tests := []struct {
+		name     string
+		strategy GasStrategy
+		baseFee  int64
+		tip      int64
+		maxFee   int64
+	}{
+		{"suggested", SuggestedGas{}, 100, 30, 130},
+		{"history median", FeeHistoryGas{Percentile: 60}, 120, 35, 155},
+		{"headroom", HeadroomGas{Strategy: SuggestedGas{}, Multiplier: 2}, 100, 30, 230},
+		{"headroom below fee", HeadroomGas{Strategy: SuggestedGas{}, Multiplier: 0.5}, 100, 30, 130},
+		{"capped", CappedGas{Strategy: HeadroomGas{Strategy: SuggestedGas{}, Multiplier: 2}, MaxFeeGwei: 150, MaxTipGwei: 20}, 100, 20, 150},
+		{"cap below tip", CappedGas{Strategy: SuggestedGas{}, MaxFeeGwei: 25}, 100, 25, 25},
+		{"no caps", CappedGas{Strategy: SuggestedGas{}}, 100, 30, 130},
+		{"fixed", FixedGas{TipGwei: 50, MaxFeeGwei: 500}, 100, 50, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees, err := tt.strategy.Fees(context.Background(), client)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, got := range []struct {
+				name     string
+				have     *big.Int
+				wantGwei int64
+			}{
+				{"base fee", fees.BaseFee, tt.baseFee},
+				{"tip", fees.TipCap, tt.tip},
+				{"max fee", fees.MaxFeePerGas, tt.maxFee},
+			} {
+				if want := Gwei(float64(got.wantGwei)); got.have.Cmp(want) != 0 {
+					t.Errorf("%s %s, want %s", got.name, got.have, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFeeHistoryGasEmpty(t *testing.T) {
+	if _, err := (FeeHistoryGas{}).Fees(context.Background(), stubFees{history: new(ethereum.FeeHistory)}); err == nil {
+		t.Fatal("priced from an empty fee history")
+	}
+
+	// Blocks without rewards leave the tip at zero.
+	fees, err := (FeeHistoryGas{}).Fees(context.Background(), stubFees{history: gweiHistory([]int64{80})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fees.TipCap.Sign() != 0 || fees.MaxFeePerGas.Cmp(Gwei(80)) != 0 {
+		t.Errorf("tip %s max fee %s, want 0 and 80 gwei", fees.TipCap, fees.MaxFeePerGas)
+	}
+}
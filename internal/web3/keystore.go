@@ -0,0 +1,121 @@
+package web3
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// NewFromKeystore decrypts an Ethereum V3 JSON keystore (scrypt or pbkdf2)
+// and connects a wallet for the key inside it.
+func NewFromKeystore(rpc, keystorePath, passphrase string) (*Wallet, error) {
+	pKey, err := LoadKeystore(keystorePath, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromKey(rpc, pKey)
+}
+
+func LoadKeystore(keystorePath, passphrase string) (*ecdsa.PrivateKey, error) {
+	keyJSON, err := os.ReadFile(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("can't read keystore file: %w", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("can't decrypt keystore %s: %w", filepath.Base(keystorePath), err)
+	}
+
+	return key.PrivateKey, nil
+}
+
+// KeystoreFiles returns keystorePath itself if it is a file, or every
+// keystore file inside it if it is a directory. Other files in the
+// directory, like a README, are skipped.
+func KeystoreFiles(keystorePath string) ([]string, error) {
+	info, err := os.Stat(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("can't open keystore path: %w", err)
+	}
+
+	if !info.IsDir() {
+		return []string{keystorePath}, nil
+	}
+
+	entries, err := os.ReadDir(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("can't read keystore dir: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		file := filepath.Join(keystorePath, entry.Name())
+		if !isKeystoreFile(file) {
+			log.Printf("Skipping %s: not a keystore file", file)
+			continue
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// isKeystoreFile tells whether path holds V3 keystore JSON with an
+// encrypted key.
+func isKeystoreFile(path string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var key struct {
+		Crypto  json.RawMessage `json:"crypto"`
+		Version int             `json:"version"`
+	}
+	if err := json.Unmarshal(content, &key); err != nil {
+		return false
+	}
+
+	return key.Version == 3 && len(key.Crypto) > 0
+}
+
+func CreateKeystore(dir, passphrase string) (common.Address, error) {
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+
+	account, err := ks.NewAccount(passphrase)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("can't create keystore account: %w", err)
+	}
+
+	return account.Address, nil
+}
+
+func ImportKeystore(dir, privateKey, passphrase string) (common.Address, error) {
+	pKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privateKey), "0x"))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("can't convert private key: %w", err)
+	}
+
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+
+	account, err := ks.ImportECDSA(pKey, passphrase)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("can't import private key: %w", err)
+	}
+
+	return account.Address, nil
+}
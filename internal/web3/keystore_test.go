@@ -0,0 +1,90 @@
+package web3
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// writeTestKeystore stores a new key in dir with light scrypt parameters.
+func writeTestKeystore(t *testing.T, dir, passphrase string) accounts.Account {
+	t.Helper()
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.NewAccount(passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return account
+}
+
+func TestLoadKeystore(t *testing.T) {
+	dir := t.TempDir()
+	account := writeTestKeystore(t, dir, "correct horse")
+	file := account.URL.Path
+
+	key, err := LoadKeystore(file, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address := crypto.PubkeyToAddress(key.PublicKey); address != account.Address {
+		t.Errorf("loaded the key of %s, want %s", address, account.Address)
+	}
+
+	if _, err := LoadKeystore(file, "battery staple"); err == nil {
+		t.Error("keystore decrypted with a wrong passphrase")
+	}
+	if _, err := LoadKeystore(filepath.Join(dir, "missing.json"), "correct horse"); err == nil {
+		t.Error("missing keystore loaded")
+	}
+}
+
+func TestKeystoreFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := writeTestKeystore(t, dir, "pass").URL.Path
+	second := writeTestKeystore(t, dir, "pass").URL.Path
+
+	for name, content := range map[string]string{
+		"README.md":   "# keys\n",
+		"notes.json":  `{"version":3}`,
+		".hidden":     "{}",
+		"broken.json": `{"version":3,"crypto":`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "backup"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := KeystoreFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(files)
+	want := []string{first, second}
+	slices.Sort(want)
+	if !slices.Equal(files, want) {
+		t.Errorf("keystore files %v, want %v", files, want)
+	}
+
+	// A single file is taken as given.
+	files, err = KeystoreFiles(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(files, []string{first}) {
+		t.Errorf("keystore files %v, want %s", files, first)
+	}
+
+	if _, err := KeystoreFiles(filepath.Join(dir, "missing")); err == nil {
+		t.Error("listed a missing path")
+	}
+}
@@ -0,0 +1,425 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// MultiClient spreads calls over several RPC endpoints of the same chain.
+// Reads go to the healthiest endpoint and fail over to the next one on
+// transport errors, lagging or failed endpoints being the last resort.
+// Nonce reads and transaction submission stick to a single healthy writer
+// endpoint, so a nonce we got and the tx we send with it are seen by the
+// same node; the writer only moves when it stops answering.
+type MultiClient struct {
+	// MaxLag is how many blocks an endpoint may be behind the best head and
+	// still be healthy.
+	MaxLag uint64
+
+	endpoints []*endpoint
+	chainID   *big.Int
+
+	mu     sync.RWMutex
+	writer *endpoint
+}
+
+type endpoint struct {
+	url    string
+	client *ethclient.Client
+
+	healthy bool
+	// wrongChain endpoints are never used, lagging ones only for reads
+	// when no healthy endpoint answers.
+	wrongChain bool
+	lagging    bool
+	head       uint64
+	latency    time.Duration
+	lastErr    error
+}
+
+// EndpointStatus is the health of one endpoint as of the last check.
+type EndpointStatus struct {
+	URL     string
+	Healthy bool
+	Head    uint64
+	Latency time.Duration
+	Err     error
+}
+
+func NewMultiClient(ctx context.Context, urls []string) (*MultiClient, error) {
+	m := &MultiClient{MaxLag: 5}
+
+	for _, url := range urls {
+		client, err := ethclient.DialContext(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("can't connect to RPC %s: %w", url, err)
+		}
+		m.endpoints = append(m.endpoints, &endpoint{url: url, client: client})
+	}
+
+	if err := m.HealthCheck(ctx); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// HealthCheck queries chain ID and head of every endpoint. The chain ID
+// most endpoints report on the first check is pinned, endpoints on another
+// chain are never used.
+func (m *MultiClient) HealthCheck(ctx context.Context) error {
+	type check struct {
+		chainID *big.Int
+		head    uint64
+		latency time.Duration
+		err     error
+	}
+
+	checks := make([]check, len(m.endpoints))
+
+	var wg sync.WaitGroup
+	for i, ep := range m.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+
+			start := time.Now()
+			chainID, err := ep.client.ChainID(ctx)
+			if err != nil {
+				checks[i].err = fmt.Errorf("error getting chain id: %w", err)
+				return
+			}
+
+			head, err := ep.client.BlockNumber(ctx)
+			if err != nil {
+				checks[i].err = fmt.Errorf("error getting block number: %w", err)
+				return
+			}
+
+			checks[i] = check{chainID: chainID, head: head, latency: time.Since(start)}
+		}(i, ep)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.chainID == nil {
+		votes := make(map[string]int)
+		for _, c := range checks {
+			if c.err != nil {
+				continue
+			}
+			votes[c.chainID.String()]++
+			if m.chainID == nil || votes[c.chainID.String()] > votes[m.chainID.String()] {
+				m.chainID = c.chainID
+			}
+		}
+	}
+
+	if m.chainID == nil {
+		return errors.New("no RPC endpoint answered")
+	}
+
+	var bestHead uint64
+	for _, c := range checks {
+		if c.err != nil {
+			continue
+		}
+		if c.chainID.Cmp(m.chainID) == 0 && c.head > bestHead {
+			bestHead = c.head
+		}
+	}
+
+	healthy := 0
+	for i, ep := range m.endpoints {
+		c := checks[i]
+		ep.head, ep.latency, ep.lastErr = c.head, c.latency, c.err
+		ep.lagging = false
+
+		switch {
+		case c.err != nil:
+			ep.healthy = false
+		case c.chainID.Cmp(m.chainID) != 0:
+			ep.healthy = false
+			ep.wrongChain = true
+			ep.lastErr = fmt.Errorf("wrong chain id %s, expected %s", c.chainID, m.chainID)
+		case bestHead-c.head > m.MaxLag:
+			ep.healthy = false
+			ep.lagging = true
+			ep.lastErr = fmt.Errorf("%d blocks behind", bestHead-c.head)
+		default:
+			ep.healthy = true
+			ep.wrongChain = false
+			healthy++
+		}
+	}
+
+	if healthy == 0 {
+		return errors.New("no healthy RPC endpoint")
+	}
+
+	if m.writer == nil || !m.writer.healthy {
+		m.writer = m.ranked(true)[0]
+	}
+
+	return nil
+}
+
+// Run repeats HealthCheck every interval until ctx is done.
+func (m *MultiClient) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.HealthCheck(ctx); err != nil {
+				log.Printf("RPC health check: %v", err)
+			}
+		}
+	}
+}
+
+func (m *MultiClient) Status() []EndpointStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := make([]EndpointStatus, 0, len(m.endpoints))
+	for _, ep := range m.endpoints {
+		status = append(status, EndpointStatus{
+			URL:     ep.url,
+			Healthy: ep.healthy,
+			Head:    ep.head,
+			Latency: ep.latency,
+			Err:     ep.lastErr,
+		})
+	}
+
+	return status
+}
+
+// ranked orders the endpoints a call may use, healthy ones first, then by
+// head and latency. Endpoints on another chain are left out, and writes
+// only get healthy endpoints. The caller holds m.mu.
+func (m *MultiClient) ranked(write bool) []*endpoint {
+	var ranked []*endpoint
+	for _, ep := range m.endpoints {
+		if ep.wrongChain || write && !ep.healthy {
+			continue
+		}
+		ranked = append(ranked, ep)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.healthy != b.healthy {
+			return a.healthy
+		}
+		if a.head != b.head {
+			return a.head > b.head
+		}
+		return a.latency < b.latency
+	})
+
+	return ranked
+}
+
+func (m *MultiClient) markFailed(ep *endpoint, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ep.healthy = false
+	ep.lastErr = err
+	log.Printf("RPC %s failed: %v", ep.url, err)
+
+	if m.writer == ep {
+		m.writer = nil
+		if writers := m.ranked(true); len(writers) > 0 {
+			m.writer = writers[0]
+		}
+	}
+}
+
+// call runs fn on endpoints in order of preference until one of them gives
+// an answer. Writes start at the writer endpoint.
+func call[T any](ctx context.Context, m *MultiClient, write bool, fn func(*ethclient.Client) (T, error)) (T, error) {
+	m.mu.RLock()
+	order := m.ranked(write)
+	if write && m.writer != nil {
+		order = append([]*endpoint{m.writer}, order...)
+	}
+	m.mu.RUnlock()
+
+	var (
+		zero T
+		err  = errors.New("no usable RPC endpoint")
+		done = make(map[*endpoint]bool)
+	)
+	for _, ep := range order {
+		if done[ep] {
+			continue
+		}
+		done[ep] = true
+
+		var res T
+		res, err = fn(ep.client)
+		if err == nil || !shouldFailover(ctx, err) {
+			return res, err
+		}
+
+		m.markFailed(ep, err)
+	}
+
+	return zero, err
+}
+
+// shouldFailover tells transport trouble apart from answers: a JSON-RPC
+// error like "execution reverted" or "nonce too low" would be the same on
+// any node.
+func shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.ErrorCode() {
+		case -32601, -32603, -32005:
+			// method not found, internal error, rate limited
+			return true
+		}
+		return false
+	}
+
+	return true
+}
+
+func (m *MultiClient) ChainID(ctx context.Context) (*big.Int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return new(big.Int).Set(m.chainID), nil
+}
+
+func (m *MultiClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) (uint64, error) {
+		return c.BlockNumber(ctx)
+	})
+}
+
+func (m *MultiClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) (*types.Header, error) {
+		return c.HeaderByNumber(ctx, number)
+	})
+}
+
+func (m *MultiClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) (*big.Int, error) {
+		return c.BalanceAt(ctx, account, blockNumber)
+	})
+}
+
+func (m *MultiClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) ([]byte, error) {
+		return c.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+func (m *MultiClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) ([]byte, error) {
+		return c.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+func (m *MultiClient) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) (uint64, error) {
+		return c.EstimateGas(ctx, msg)
+	})
+}
+
+func (m *MultiClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) (*big.Int, error) {
+		return c.SuggestGasPrice(ctx)
+	})
+}
+
+func (m *MultiClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) (*big.Int, error) {
+		return c.SuggestGasTipCap(ctx)
+	})
+}
+
+func (m *MultiClient) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) (*ethereum.FeeHistory, error) {
+		return c.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+func (m *MultiClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) (*types.Receipt, error) {
+		return c.TransactionReceipt(ctx, txHash)
+	})
+}
+
+// TransactionByHash asks the writer first, the node our transactions were
+// sent to, so pending ones are found.
+func (m *MultiClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	type result struct {
+		tx        *types.Transaction
+		isPending bool
+	}
+
+	res, err := call(ctx, m, true, func(c *ethclient.Client) (result, error) {
+		tx, isPending, err := c.TransactionByHash(ctx, hash)
+		return result{tx, isPending}, err
+	})
+
+	return res.tx, res.isPending, err
+}
+
+func (m *MultiClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) ([]types.Log, error) {
+		return c.FilterLogs(ctx, q)
+	})
+}
+
+func (m *MultiClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return call(ctx, m, false, func(c *ethclient.Client) (ethereum.Subscription, error) {
+		return c.SubscribeFilterLogs(ctx, q, ch)
+	})
+}
+
+func (m *MultiClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return call(ctx, m, true, func(c *ethclient.Client) ([]byte, error) {
+		return c.PendingCodeAt(ctx, account)
+	})
+}
+
+func (m *MultiClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return call(ctx, m, true, func(c *ethclient.Client) (uint64, error) {
+		return c.PendingNonceAt(ctx, account)
+	})
+}
+
+func (m *MultiClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := call(ctx, m, true, func(c *ethclient.Client) (struct{}, error) {
+		return struct{}{}, c.SendTransaction(ctx, tx)
+	})
+
+	return err
+}
@@ -0,0 +1,232 @@
+package web3
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testNode is the part of the eth namespace MultiClient needs, served over
+// HTTP. Setting down makes it answer every request with 503.
+type testNode struct {
+	url     string
+	chainID int64
+	head    uint64
+	served  atomic.Int64
+	down    atomic.Bool
+}
+
+func (n *testNode) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(n.chainID))
+}
+
+func (n *testNode) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(n.head)
+}
+
+func (n *testNode) GetBalance(common.Address, string) *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(int64(n.head)))
+}
+
+func (n *testNode) GetTransactionCount(common.Address, string) hexutil.Uint64 {
+	return 7
+}
+
+func newTestNode(t *testing.T, head uint64) *testNode {
+	t.Helper()
+
+	node := &testNode{chainID: 137, head: head}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", node); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if node.down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		node.served.Add(1)
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(httpServer.Close)
+
+	node.url = httpServer.URL
+
+	return node
+}
+
+func TestMultiClientFailover(t *testing.T) {
+	ctx := context.Background()
+
+	// primary is ahead, so it is preferred for reads and writes until it
+	// fails.
+	primary := newTestNode(t, 101)
+	backup := newTestNode(t, 100)
+
+	client, err := NewMultiClient(ctx, []string{primary.url, backup.url})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	balance, err := client.BalanceAt(ctx, common.Address{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Uint64() != 101 {
+		t.Fatalf("read went to head %d, want the primary", balance.Uint64())
+	}
+
+	primary.down.Store(true)
+	servedBefore := backup.served.Load()
+
+	balance, err = client.BalanceAt(ctx, common.Address{}, nil)
+	if err != nil {
+		t.Fatalf("read after primary failed: %v", err)
+	}
+	if balance.Uint64() != 100 {
+		t.Fatalf("read went to head %d, want the backup", balance.Uint64())
+	}
+
+	nonce, err := client.PendingNonceAt(ctx, common.Address{})
+	if err != nil {
+		t.Fatalf("write after primary failed: %v", err)
+	}
+	if nonce != 7 {
+		t.Fatalf("nonce %d, want 7", nonce)
+	}
+
+	if backup.served.Load() <= servedBefore {
+		t.Fatal("backup served nothing after failover")
+	}
+
+	for _, status := range client.Status() {
+		if status.URL == primary.url && status.Healthy {
+			t.Error("failed primary is still healthy")
+		}
+		if status.URL == backup.url && !status.Healthy {
+			t.Errorf("backup is unhealthy: %v", status.Err)
+		}
+	}
+}
+
+func TestMultiClientStartsWithDeadEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	dead := newTestNode(t, 100)
+	dead.down.Store(true)
+	alive := newTestNode(t, 100)
+
+	client, err := NewMultiClient(ctx, []string{dead.url, alive.url})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.BlockNumber(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if dead.served.Load() != 0 {
+		t.Fatal("dead endpoint served a request")
+	}
+
+	alive.down.Store(true)
+	if _, err := client.BlockNumber(ctx); err == nil {
+		t.Fatal("call succeeded with every endpoint down")
+	}
+}
+
+func TestMultiClientWrongChain(t *testing.T) {
+	ctx := context.Background()
+
+	a := newTestNode(t, 100)
+	b := newTestNode(t, 100)
+	// other is far ahead, but on another chain.
+	other := newTestNode(t, 500)
+	other.chainID = 80002
+
+	client, err := NewMultiClient(ctx, []string{other.url, a.url, b.url})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if chainID, _ := client.ChainID(ctx); chainID.Int64() != 137 {
+		t.Fatalf("chain id %s, want the majority's 137", chainID)
+	}
+
+	servedBefore := other.served.Load()
+
+	if balance, err := client.BalanceAt(ctx, common.Address{}, nil); err != nil || balance.Uint64() != 100 {
+		t.Fatalf("read got %v %v, want head 100", balance, err)
+	}
+	if _, err := client.PendingNonceAt(ctx, common.Address{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// With the right chain down, nothing may go to the other chain.
+	a.down.Store(true)
+	b.down.Store(true)
+	if _, err := client.BalanceAt(ctx, common.Address{}, nil); err == nil {
+		t.Error("read answered by an endpoint on another chain")
+	}
+	if _, err := client.PendingNonceAt(ctx, common.Address{}); err == nil {
+		t.Error("write answered by an endpoint on another chain")
+	}
+
+	if other.served.Load() != servedBefore {
+		t.Error("endpoint on another chain served a call")
+	}
+}
+
+func TestMultiClientHeadLag(t *testing.T) {
+	ctx := context.Background()
+
+	current := newTestNode(t, 100)
+	lagging := newTestNode(t, 50)
+
+	client, err := NewMultiClient(ctx, []string{lagging.url, current.url})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, status := range client.Status() {
+		if status.URL == lagging.url && status.Healthy {
+			t.Fatal("endpoint 50 blocks behind is healthy")
+		}
+	}
+
+	servedBefore := lagging.served.Load()
+
+	if balance, err := client.BalanceAt(ctx, common.Address{}, nil); err != nil || balance.Uint64() != 100 {
+		t.Fatalf("read got %v %v, want head 100", balance, err)
+	}
+	if _, err := client.PendingNonceAt(ctx, common.Address{}); err != nil {
+		t.Fatal(err)
+	}
+	if lagging.served.Load() != servedBefore {
+		t.Fatal("lagging endpoint served a call while a healthy one was up")
+	}
+
+	// Reads fall back to the lagging endpoint, writes don't.
+	current.down.Store(true)
+
+	if balance, err := client.BalanceAt(ctx, common.Address{}, nil); err != nil || balance.Uint64() != 50 {
+		t.Fatalf("read got %v %v, want the lagging endpoint's head 50", balance, err)
+	}
+
+	servedBefore = lagging.served.Load()
+	if _, err := client.PendingNonceAt(ctx, common.Address{}); err == nil {
+		t.Error("write went to the lagging endpoint")
+	}
+	if lagging.served.Load() != servedBefore {
+		t.Error("lagging endpoint served a write")
+	}
+}
@@ -0,0 +1,140 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var multiSendABI = mustABI(`[{"name":"multiSend","type":"function","stateMutability":"payable","inputs":[{"name":"transactions","type":"bytes"}],"outputs":[]}]`)
+
+// MultiSendCall is one call of a MultiSend batch made by the Safe.
+type MultiSendCall struct {
+	Operation uint8
+	To        common.Address
+	Value     *big.Int
+	Data      []byte
+}
+
+func (c MultiSendCall) String() string {
+	selector := "0x"
+	if len(c.Data) >= 4 {
+		selector = hexutil.Encode(c.Data[:4])
+	}
+
+	return fmt.Sprintf("op=%d to=%s value=%s selector=%s data=%d bytes", c.Operation, c.To, NewAmount(c.Value, 18), selector, len(c.Data))
+}
+
+// EncodeMultiSend packs calls into multiSend calldata. Every call is
+// operation (1 byte), to (20), value (32), data length (32) and data. Only
+// plain calls are allowed in a batch.
+func EncodeMultiSend(calls []MultiSendCall) ([]byte, error) {
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("empty multisend batch")
+	}
+
+	var packed []byte
+	for i, call := range calls {
+		if call.Operation != SafeCall {
+			return nil, fmt.Errorf("call %d: operation %d, only calls are allowed in a batch", i, call.Operation)
+		}
+
+		value := call.Value
+		if value == nil {
+			value = new(big.Int)
+		}
+		if value.Sign() < 0 {
+			return nil, fmt.Errorf("call %d: negative value", i)
+		}
+
+		packed = append(packed, call.Operation)
+		packed = append(packed, call.To.Bytes()...)
+		packed = append(packed, common.LeftPadBytes(value.Bytes(), 32)...)
+		packed = append(packed, common.LeftPadBytes(big.NewInt(int64(len(call.Data))).Bytes(), 32)...)
+		packed = append(packed, call.Data...)
+	}
+
+	return multiSendABI.Pack("multiSend", packed)
+}
+
+// DecodeMultiSend unpacks multiSend calldata back into its calls, rejecting
+// batches with anything but plain calls.
+func DecodeMultiSend(data []byte) ([]MultiSendCall, error) {
+	method, ok := multiSendABI.Methods["multiSend"]
+	if !ok || len(data) < 4 || string(data[:4]) != string(method.ID) {
+		return nil, fmt.Errorf("not multiSend calldata")
+	}
+
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack multiSend: %w", err)
+	}
+	packed := args[0].([]byte)
+
+	var calls []MultiSendCall
+	for len(packed) > 0 {
+		if len(packed) < 85 {
+			return nil, fmt.Errorf("truncated multisend call %d", len(calls))
+		}
+
+		if packed[0] != SafeCall {
+			return nil, fmt.Errorf("multisend call %d: operation %d, only calls are allowed in a batch", len(calls), packed[0])
+		}
+
+		length := new(big.Int).SetBytes(packed[53:85])
+		if !length.IsUint64() || length.Uint64() > uint64(len(packed)-85) {
+			return nil, fmt.Errorf("multisend call %d: invalid data length %s", len(calls), length)
+		}
+		end := 85 + int(length.Uint64())
+
+		calls = append(calls, MultiSendCall{
+			Operation: packed[0],
+			To:        common.BytesToAddress(packed[1:21]),
+			Value:     new(big.Int).SetBytes(packed[21:53]),
+			Data:      common.CopyBytes(packed[85:end]),
+		})
+		packed = packed[end:]
+	}
+
+	return calls, nil
+}
+
+// MultiSendTx is the Safe delegatecall running calls as one batch through
+// MultiSendCallOnly.
+func (n *Network) MultiSendTx(calls []MultiSendCall) (common.Address, []byte, error) {
+	if n.MultiSendCallOnly == (common.Address{}) {
+		return common.Address{}, nil, fmt.Errorf("no MultiSendCallOnly contract on %s", n.Name)
+	}
+
+	data, err := EncodeMultiSend(calls)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	return n.MultiSendCallOnly, data, nil
+}
+
+// CheckDelegateCall rejects a delegatecall from a Safe to anything but the
+// network's MultiSendCallOnly, which would run foreign code with the Safe's
+// storage.
+func (n *Network) CheckDelegateCall(to common.Address) error {
+	if n.MultiSendCallOnly == (common.Address{}) || to != n.MultiSendCallOnly {
+		return fmt.Errorf("delegatecall to %s, only MultiSendCallOnly %s is allowed on %s", to, n.MultiSendCallOnly, n.Name)
+	}
+
+	return nil
+}
+
+// ExecuteBatch has the Safe run calls in one transaction through
+// MultiSendCallOnly.
+func (s *Safe) ExecuteBatch(ctx context.Context, calls []MultiSendCall) (*PendingTx, error) {
+	to, data, err := s.wallet.Network.MultiSendTx(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Execute(ctx, to, nil, data, SafeDelegateCall)
+}
@@ -0,0 +1,71 @@
+package web3
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMultiSendRoundTrip(t *testing.T) {
+	calls := []MultiSendCall{
+		{To: Polygon.USDC, Value: new(big.Int), Data: []byte{0x09, 0x5e, 0xa7, 0xb3, 1, 2, 3}},
+		{To: Polygon.ConditionalTokens, Value: big.NewInt(5)},
+	}
+
+	to, data, err := Polygon.MultiSendTx(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if to != Polygon.MultiSendCallOnly {
+		t.Errorf("batch goes to %s, want MultiSendCallOnly", to)
+	}
+	if err := Polygon.CheckDelegateCall(to); err != nil {
+		t.Error(err)
+	}
+
+	decoded, err := DecodeMultiSend(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(calls) {
+		t.Fatalf("decoded %d calls, want %d", len(decoded), len(calls))
+	}
+	for i, call := range decoded {
+		if call.Operation != SafeCall || call.To != calls[i].To || call.Value.Cmp(calls[i].Value) != 0 || !bytes.Equal(call.Data, calls[i].Data) {
+			t.Errorf("call %d decoded as %s, want %s", i, call, calls[i])
+		}
+	}
+}
+
+func TestMultiSendRejectsDelegateCalls(t *testing.T) {
+	calls := []MultiSendCall{
+		{To: Polygon.USDC},
+		{Operation: SafeDelegateCall, To: common.HexToAddress("0x01")},
+	}
+
+	if _, err := EncodeMultiSend(calls); err == nil {
+		t.Fatal("batch with a delegatecall was encoded")
+	}
+
+	// The same batch packed by hand, as a relayer or another tool could.
+	var packed []byte
+	for _, call := range calls {
+		packed = append(packed, call.Operation)
+		packed = append(packed, call.To.Bytes()...)
+		packed = append(packed, make([]byte, 64)...)
+	}
+	data, err := multiSendABI.Pack("multiSend", packed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := DecodeMultiSend(data); err == nil {
+		t.Fatal("batch with a delegatecall was decoded")
+	}
+
+	if err := Polygon.CheckDelegateCall(common.HexToAddress("0xA238CBeb142c10Ef7Ad8442C6D1f9E89e07e7761")); err == nil {
+		t.Error("delegatecall to the full MultiSend was allowed")
+	}
+}
@@ -0,0 +1,130 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"polymarket/internal/ctfid"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var negRiskAdapterABI = mustABI(`[
+	{"name":"redeemPositions","type":"function","stateMutability":"nonpayable","inputs":[{"name":"conditionId","type":"bytes32"},{"name":"amounts","type":"uint256[]"}],"outputs":[]},
+	{"name":"splitPosition","type":"function","stateMutability":"nonpayable","inputs":[{"name":"conditionId","type":"bytes32"},{"name":"amount","type":"uint256"}],"outputs":[]},
+	{"name":"mergePositions","type":"function","stateMutability":"nonpayable","inputs":[{"name":"conditionId","type":"bytes32"},{"name":"amount","type":"uint256"}],"outputs":[]},
+	{"name":"convertPositions","type":"function","stateMutability":"nonpayable","inputs":[{"name":"marketId","type":"bytes32"},{"name":"indexSet","type":"uint256"},{"name":"amount","type":"uint256"}],"outputs":[]}
+]`)
+
+// NegRiskRedeemData is the adapter calldata redeeming amounts[i] of every
+// outcome i of a neg-risk condition.
+func NegRiskRedeemData(conditionID common.Hash, amounts []*big.Int) ([]byte, error) {
+	return negRiskAdapterABI.Pack("redeemPositions", conditionID, amounts)
+}
+
+// Exchange is the exchange settling orders of a market.
+func (n *Network) Exchange(negRisk bool) common.Address {
+	if negRisk {
+		return n.NegRiskExchange
+	}
+
+	return n.CTFExchange
+}
+
+// Splitter is the contract splitting, merging and redeeming positions of a
+// market: the CTF itself, or the adapter for neg-risk markets.
+func (n *Network) Splitter(negRisk bool) common.Address {
+	if negRisk {
+		return n.NegRiskAdapter
+	}
+
+	return n.ConditionalTokens
+}
+
+// NegRiskQuestionID is the id of question index of a neg-risk market; the
+// market id has its last byte zeroed for it.
+func NegRiskQuestionID(marketID common.Hash, index uint8) common.Hash {
+	questionID := marketID
+	questionID[common.HashLength-1] = index
+
+	return questionID
+}
+
+// NegRiskConditionID is the condition the adapter prepared for question
+// index of a neg-risk market.
+func (n *Network) NegRiskConditionID(marketID common.Hash, index uint8) common.Hash {
+	return ctfid.ConditionID(n.NegRiskAdapter, NegRiskQuestionID(marketID, index), binaryOutcomes)
+}
+
+// ConvertCalls are the Safe calls converting amount of NO of every question
+// in indexSet of a neg-risk market into amount of YES of every other
+// question, plus USDC.e for all but one of the converted NOs.
+func (s *Safe) ConvertCalls(ctx context.Context, marketID common.Hash, indexSet *big.Int, amount Amount) ([]MultiSendCall, error) {
+	if marketID[common.HashLength-1] != 0 {
+		return nil, fmt.Errorf("invalid neg-risk market id %s", marketID)
+	}
+	if indexSet == nil || indexSet.Sign() <= 0 || indexSet.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid question index set %v", indexSet)
+	}
+	if amount.Decimals != USDCDecimals || amount.Raw == nil || amount.Raw.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount %s, want a positive USDC.e amount", amount)
+	}
+
+	network := s.wallet.Network
+	ctf := s.wallet.CTF()
+
+	var ids []*big.Int
+	for i := 0; i < indexSet.BitLen(); i++ {
+		if indexSet.Bit(i) == 0 {
+			continue
+		}
+
+		positions, err := network.OutcomePositions(network.NegRiskConditionID(marketID, uint8(i)), true)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, positions[1].ID)
+	}
+
+	balances, err := ctf.BalanceOfBatch(ctx, s.Address, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, balance := range balances {
+		if balance.Cmp(amount) < 0 {
+			return nil, fmt.Errorf("proxy holds %s of NO position %s, can't convert %s", balance, ids[i], amount)
+		}
+	}
+
+	var calls []MultiSendCall
+
+	approved, err := ctf.IsApprovedForAll(ctx, s.Address, network.NegRiskAdapter)
+	if err != nil {
+		return nil, err
+	}
+	if !approved {
+		data, err := ctf.SetApprovalForAllData(network.NegRiskAdapter, true)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, MultiSendCall{To: ctf.Address, Data: data})
+	}
+
+	data, err := negRiskAdapterABI.Pack("convertPositions", marketID, indexSet, amount.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack convertPositions: %w", err)
+	}
+
+	return append(calls, MultiSendCall{To: network.NegRiskAdapter, Data: data}), nil
+}
+
+// Convert has the Safe convert NO positions, paid by the wallet's EOA.
+func (s *Safe) Convert(ctx context.Context, marketID common.Hash, indexSet *big.Int, amount Amount) (*PendingTx, error) {
+	calls, err := s.ConvertCalls(ctx, marketID, indexSet, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ExecuteBatch(ctx, calls)
+}
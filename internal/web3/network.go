@@ -0,0 +1,78 @@
+package web3
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Network holds the Polymarket contracts of one chain.
+type Network struct {
+	Name    string
+	ChainID *big.Int
+
+	// Gnosis Safe wallets of browser wallet users.
+	SafeFactory      common.Address
+	SafeInitCodeHash common.Hash
+
+	// Polymarket Proxy wallets of email/Magic users.
+	ProxyFactory      common.Address
+	ProxyInitCodeHash common.Hash
+
+	// MultiSendCallOnly is the Safe v1.3 MultiSendCallOnly contract, the
+	// only target a Safe is allowed to delegatecall. Unlike MultiSend it
+	// reverts on nested delegatecalls.
+	MultiSendCallOnly common.Address
+
+	// USDC is the bridged USDC.e used as collateral.
+	USDC common.Address
+	// ConditionalTokens is the Gnosis CTF (ERC-1155) holding outcome tokens.
+	ConditionalTokens common.Address
+
+	// CTFExchange settles orders of plain binary markets. Markets of
+	// multi-outcome (neg-risk) events trade on NegRiskExchange and split,
+	// merge, redeem and convert through NegRiskAdapter.
+	CTFExchange     common.Address
+	NegRiskExchange common.Address
+	NegRiskAdapter  common.Address
+
+	// NegRiskCollateral is the adapter's wrapped USDC.e backing neg-risk
+	// positions.
+	NegRiskCollateral common.Address
+}
+
+var Polygon = &Network{
+	Name:    "polygon",
+	ChainID: big.NewInt(137),
+
+	SafeFactory:      common.HexToAddress("0xaacFeEa03eb1561C4e67d661e40682Bd20E3541b"),
+	SafeInitCodeHash: common.HexToHash("0x2bce2127ff07fb632d16c8347c4ebf501f4841168bed00d9e6ef715ddb6fcecf"),
+
+	ProxyFactory:      common.HexToAddress("0xaB45c5A4B0c941a2F231C04C3f49182e1A254052"),
+	ProxyInitCodeHash: common.HexToHash("0xd21df8dc65880a8606f09fe0ce3df9b8869287ab0b058be05aa9e8af6330a00b"),
+
+	MultiSendCallOnly: common.HexToAddress("0x40A2aCCbd92BCA938b02010E17A5b8929b49130D"),
+
+	USDC:              common.HexToAddress("0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"),
+	ConditionalTokens: common.HexToAddress("0x4D97DCd97eC945f40cF65F87097ACe5EA0476045"),
+
+	CTFExchange:     common.HexToAddress("0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E"),
+	NegRiskExchange: common.HexToAddress("0xC5d563A36AE78145C45a50134d48A1215220f80a"),
+	NegRiskAdapter:  common.HexToAddress("0xd91E80cF2E7be2e162c6513ceD06f1dD0dA35296"),
+
+	NegRiskCollateral: common.HexToAddress("0x3A3BD7bb9528E159577F7C2e685CC81A765002E2"),
+}
+
+var networks = map[string]*Network{
+	Polygon.ChainID.String(): Polygon,
+}
+
+func NetworkByChainID(chainID *big.Int) (*Network, error) {
+	network, ok := networks[chainID.String()]
+	if !ok {
+		return nil, fmt.Errorf("no Polymarket contracts known on chain %s", chainID)
+	}
+
+	return network, nil
+}
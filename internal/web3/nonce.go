@@ -0,0 +1,272 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type nonceReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+}
+
+// NonceManager hands out nonces locally so goroutines sending from the same
+// address never get the same one. A nonce is reserved, then either marked
+// sent or released back when the transaction never reached the node.
+type NonceManager struct {
+	client nonceReader
+
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonces
+}
+
+type accountNonces struct {
+	mu       sync.Mutex
+	synced   bool
+	next     uint64
+	released []uint64
+	inFlight map[uint64]bool
+	// sent is the hash of the last transaction broadcast with each nonce
+	// the node may not have mined yet. A zero hash means the node refused
+	// ours because another transaction already holds the nonce.
+	sent map[uint64]common.Hash
+}
+
+func NewNonceManager(client nonceReader) *NonceManager {
+	return &NonceManager{
+		client:   client,
+		accounts: make(map[common.Address]*accountNonces),
+	}
+}
+
+func (m *NonceManager) account(address common.Address) *accountNonces {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc, ok := m.accounts[address]
+	if !ok {
+		acc = &accountNonces{inFlight: make(map[uint64]bool), sent: make(map[uint64]common.Hash)}
+		m.accounts[address] = acc
+	}
+
+	return acc
+}
+
+// Reserve returns the next free nonce of address. Released nonces are
+// handed out again first so no gap is left behind.
+func (m *NonceManager) Reserve(ctx context.Context, address common.Address) (uint64, error) {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.synced {
+		pending, err := m.client.PendingNonceAt(ctx, address)
+		if err != nil {
+			return 0, fmt.Errorf("error getting nonce: %w", err)
+		}
+		acc.next = pending
+		acc.synced = true
+	}
+
+	var nonce uint64
+	if len(acc.released) > 0 {
+		nonce = acc.released[0]
+		acc.released = acc.released[1:]
+	} else {
+		nonce = acc.next
+		acc.next++
+	}
+	acc.inFlight[nonce] = true
+
+	return nonce, nil
+}
+
+// Release gives back a reserved nonce whose transaction was never
+// broadcast, or a gap from TakeGaps that was not filled.
+func (m *NonceManager) Release(address common.Address, nonce uint64) {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.inFlight[nonce] {
+		return
+	}
+	delete(acc.inFlight, nonce)
+	delete(acc.sent, nonce)
+
+	acc.released = append(acc.released, nonce)
+	sort.Slice(acc.released, func(i, j int) bool { return acc.released[i] < acc.released[j] })
+
+	for n := len(acc.released); n > 0 && acc.released[n-1]+1 == acc.next; n-- {
+		acc.released = acc.released[:n-1]
+		acc.next--
+	}
+}
+
+// MarkSent records that the transaction hash with nonce reached the node.
+// A zero hash records that another transaction holds the nonce.
+func (m *NonceManager) MarkSent(address common.Address, nonce uint64, hash common.Hash) {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	delete(acc.inFlight, nonce)
+	acc.sent[nonce] = hash
+}
+
+// Resync moves the local counter forward to the node's pending nonce. It is
+// called after "nonce too low" or "replacement underpriced", when someone
+// else already used the nonce we handed out.
+func (m *NonceManager) Resync(ctx context.Context, address common.Address) error {
+	pending, err := m.client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return fmt.Errorf("error getting nonce: %w", err)
+	}
+
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.synced || pending > acc.next {
+		acc.next = pending
+	}
+	acc.synced = true
+
+	released := acc.released[:0]
+	for _, nonce := range acc.released {
+		if nonce >= pending {
+			released = append(released, nonce)
+		}
+	}
+	acc.released = released
+	acc.forgetBelow(pending)
+
+	return nil
+}
+
+// forgetBelow drops the sent hashes of nonces the node has used up. The
+// caller holds acc.mu.
+func (acc *accountNonces) forgetBelow(pending uint64) {
+	for nonce := range acc.sent {
+		if nonce < pending {
+			delete(acc.sent, nonce)
+		}
+	}
+}
+
+// Reset forgets all local state of address, the next Reserve asks the node.
+func (m *NonceManager) Reset(address common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.accounts, address)
+}
+
+// TakeGaps returns nonces below the local counter that nobody is sending
+// and that hold no transaction: never broadcast, or dropped by the node,
+// which no longer knows the hash sent with them. Transactions queued above
+// a gap are not counted by the node's pending nonce, so every sent hash is
+// looked up before its nonce is called a gap. Gaps are reserved for the
+// caller, who must MarkSent or Release each one.
+func (m *NonceManager) TakeGaps(ctx context.Context, address common.Address) ([]uint64, error) {
+	pending, err := m.client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("error getting nonce: %w", err)
+	}
+
+	acc := m.account(address)
+	acc.mu.Lock()
+
+	acc.forgetBelow(pending)
+
+	var (
+		candidates []uint64
+		hashes     = make(map[uint64]common.Hash)
+	)
+	for nonce := pending; nonce < acc.next; nonce++ {
+		if acc.inFlight[nonce] {
+			continue
+		}
+		hash, sent := acc.sent[nonce]
+		if sent && hash == (common.Hash{}) {
+			continue
+		}
+		if sent {
+			hashes[nonce] = hash
+		}
+		candidates = append(candidates, nonce)
+		acc.inFlight[nonce] = true
+	}
+
+	acc.mu.Unlock()
+
+	var gaps, known []uint64
+	for _, nonce := range candidates {
+		hash, sent := hashes[nonce]
+		if !sent {
+			gaps = append(gaps, nonce)
+			continue
+		}
+
+		_, _, err := m.client.TransactionByHash(ctx, hash)
+		switch {
+		case errors.Is(err, ethereum.NotFound):
+			gaps = append(gaps, nonce)
+		case err != nil:
+			acc.mu.Lock()
+			for _, nonce := range candidates {
+				delete(acc.inFlight, nonce)
+			}
+			acc.mu.Unlock()
+			return nil, fmt.Errorf("error when look up tx %s: %w", hash, err)
+		default:
+			known = append(known, nonce)
+		}
+	}
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	for _, nonce := range known {
+		delete(acc.inFlight, nonce)
+	}
+
+	isGap := make(map[uint64]bool, len(gaps))
+	for _, nonce := range gaps {
+		isGap[nonce] = true
+		delete(acc.sent, nonce)
+	}
+	released := acc.released[:0]
+	for _, nonce := range acc.released {
+		if !isGap[nonce] {
+			released = append(released, nonce)
+		}
+	}
+	acc.released = released
+
+	return gaps, nil
+}
+
+// isNonceError reports whether the node rejected a transaction because its
+// nonce is already taken.
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+
+	return strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "known transaction")
+}
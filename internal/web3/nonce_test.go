@@ -0,0 +1,218 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// fakeNonces is a node with a fixed pending nonce and a set of known
+// transactions.
+type fakeNonces struct {
+	mu      sync.Mutex
+	pending uint64
+	known   map[common.Hash]bool
+	err     error
+}
+
+func (f *fakeNonces) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.pending, nil
+}
+
+func (f *fakeNonces) TransactionByHash(_ context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, false, f.err
+	}
+	if !f.known[hash] {
+		return nil, false, ethereum.NotFound
+	}
+
+	return new(types.Transaction), true, nil
+}
+
+// newSimWallet is a wallet with 100 ETH on a fresh simulated chain.
+func newSimWallet(t *testing.T) (*Wallet, *simulated.Backend) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(key.PublicKey): {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	return NewWithBackend(backend.Client(), NewKeySigner(key)), backend
+}
+
+func TestNonceManagerConcurrent(t *testing.T) {
+	ctx := context.Background()
+	address := common.HexToAddress("0x01")
+	nonces := NewNonceManager(&fakeNonces{pending: 7})
+
+	var (
+		mu   sync.Mutex
+		sent []uint64
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				nonce, err := nonces.Reserve(ctx, address)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if (i+j)%3 == 0 {
+					nonces.Release(address, nonce)
+					continue
+				}
+
+				nonces.MarkSent(address, nonce, common.BigToHash(new(big.Int).SetUint64(nonce)))
+				mu.Lock()
+				sent = append(sent, nonce)
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(sent, func(i, j int) bool { return sent[i] < sent[j] })
+	for i, nonce := range sent {
+		if nonce != 7+uint64(i) {
+			t.Fatalf("sent nonces %v..., want %d at %d: duplicate or gap", sent[:i+1], 7+i, i)
+		}
+	}
+
+	next, err := nonces.Reserve(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != 7+uint64(len(sent)) {
+		t.Errorf("next nonce %d, want %d", next, 7+len(sent))
+	}
+}
+
+func TestTakeGaps(t *testing.T) {
+	ctx := context.Background()
+	address := common.HexToAddress("0x01")
+
+	node := &fakeNonces{pending: 10, known: make(map[common.Hash]bool)}
+	nonces := NewNonceManager(node)
+
+	hash := func(nonce uint64) common.Hash { return common.BigToHash(new(big.Int).SetUint64(nonce + 1000)) }
+
+	for i := 0; i < 6; i++ {
+		if _, err := nonces.Reserve(ctx, address); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 10 was dropped, 11 is queued behind it, 12 lost to a tx sent from
+	// elsewhere, 13 never broadcast, 14 still being sent, 15 queued.
+	nonces.MarkSent(address, 10, hash(10))
+	nonces.MarkSent(address, 11, hash(11))
+	node.known[hash(11)] = true
+	nonces.MarkSent(address, 12, common.Hash{})
+	nonces.Release(address, 13)
+	nonces.MarkSent(address, 15, hash(15))
+	node.known[hash(15)] = true
+
+	node.err = errors.New("connection refused")
+	if _, err := nonces.TakeGaps(ctx, address); err == nil {
+		t.Fatal("gaps taken although a lookup failed")
+	}
+	node.err = nil
+
+	gaps, err := nonces.TakeGaps(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gaps) != 2 || gaps[0] != 10 || gaps[1] != 13 {
+		t.Fatalf("gaps %v, want [10 13]", gaps)
+	}
+
+	if again, err := nonces.TakeGaps(ctx, address); err != nil || len(again) != 0 {
+		t.Errorf("gaps taken twice: %v %v", again, err)
+	}
+
+	// A released gap is handed out again, a queued nonce never is.
+	nonces.Release(address, 13)
+	nonces.MarkSent(address, 10, hash(10))
+	for _, want := range []uint64{13, 16} {
+		nonce, err := nonces.Reserve(ctx, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nonce != want {
+			t.Errorf("reserved %d, want %d", nonce, want)
+		}
+	}
+}
+
+// TestFillNonceGapsDropped leaves nonce 0 unsent, as if the node dropped
+// its transaction, so nonce 1 waits in the queue. Only nonce 0 may be
+// filled, and then both are mined.
+func TestFillNonceGapsDropped(t *testing.T) {
+	ctx := context.Background()
+	wallet, backend := newSimWallet(t)
+	backend.Commit()
+
+	dropped, err := wallet.BuildTx(ctx, TxRequest{To: &wallet.Address, GasLimit: 21000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.Nonces.MarkSent(wallet.Address, dropped.Nonce(), dropped.Hash())
+
+	queued, err := wallet.SendTx(ctx, TxRequest{To: &common.Address{0x02}, Value: big.NewInt(1), GasLimit: 21000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued.Tx.Nonce() != 1 {
+		t.Fatalf("queued nonce %d, want 1", queued.Tx.Nonce())
+	}
+
+	fills, err := wallet.FillNonceGaps(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fills) != 1 || fills[0].Tx.Nonce() != 0 {
+		t.Fatalf("filled %d gaps, want nonce 0 only", len(fills))
+	}
+
+	backend.Commit()
+
+	for _, pending := range []*PendingTx{fills[0], queued} {
+		receipt, err := backend.Client().TransactionReceipt(ctx, pending.Hash())
+		if err != nil {
+			t.Fatalf("tx %d: %v", pending.Tx.Nonce(), err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("tx %d reverted", pending.Tx.Nonce())
+		}
+	}
+
+	if balance, err := backend.Client().BalanceAt(ctx, common.Address{0x02}, nil); err != nil || balance.Int64() != 1 {
+		t.Errorf("queued transfer not mined: balance %v %v", balance, err)
+	}
+}
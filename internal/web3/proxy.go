@@ -0,0 +1,89 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ProxyKind is the kind of proxy wallet Polymarket deploys for a user.
+type ProxyKind string
+
+const (
+	// ProxySafe is a Gnosis Safe, used for browser wallet accounts.
+	ProxySafe ProxyKind = "safe"
+	// ProxyPolymarket is a Polymarket Proxy, used for email/Magic accounts.
+	ProxyPolymarket ProxyKind = "proxy"
+)
+
+// ProxyWallet is a computed proxy address and whether it has code yet.
+type ProxyWallet struct {
+	Kind     ProxyKind
+	Address  common.Address
+	Deployed bool
+}
+
+// ProxyAddress computes the CREATE2 address of owner's proxy wallet. The
+// Safe factory salts with the ABI encoded owner, the Proxy factory with the
+// packed 20 byte owner.
+func (n *Network) ProxyAddress(kind ProxyKind, owner common.Address) (common.Address, error) {
+	switch kind {
+	case ProxySafe:
+		salt := crypto.Keccak256(common.LeftPadBytes(owner.Bytes(), 32))
+		return crypto.CreateAddress2(n.SafeFactory, common.BytesToHash(salt), n.SafeInitCodeHash.Bytes()), nil
+	case ProxyPolymarket:
+		salt := crypto.Keccak256(owner.Bytes())
+		return crypto.CreateAddress2(n.ProxyFactory, common.BytesToHash(salt), n.ProxyInitCodeHash.Bytes()), nil
+	default:
+		return common.Address{}, fmt.Errorf("unknown proxy kind %q", kind)
+	}
+}
+
+func (w *Wallet) ProxyAddress(kind ProxyKind) (common.Address, error) {
+	return w.Network.ProxyAddress(kind, w.Address)
+}
+
+// ResolveProxies computes both proxy wallets of the wallet and checks which
+// of them are deployed.
+func (w *Wallet) ResolveProxies(ctx context.Context) ([]ProxyWallet, error) {
+	var proxies []ProxyWallet
+
+	for _, kind := range []ProxyKind{ProxySafe, ProxyPolymarket} {
+		address, err := w.ProxyAddress(kind)
+		if err != nil {
+			return nil, err
+		}
+
+		code, err := w.Client.CodeAt(ctx, address, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error when get code of %s: %w", address, err)
+		}
+
+		proxies = append(proxies, ProxyWallet{
+			Kind:     kind,
+			Address:  address,
+			Deployed: len(code) > 0,
+		})
+	}
+
+	return proxies, nil
+}
+
+// DeployedProxy returns the proxy wallet that has code on-chain, preferring
+// the Safe, or nil if neither is deployed.
+func (w *Wallet) DeployedProxy(ctx context.Context) (*ProxyWallet, error) {
+	proxies, err := w.ResolveProxies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, proxy := range proxies {
+		if proxy.Deployed {
+			return &proxy, nil
+		}
+	}
+
+	return nil, nil
+}
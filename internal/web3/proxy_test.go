@@ -0,0 +1,128 @@
+package web3
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestProxyAddress(t *testing.T) {
+	// The owner is the address of private key 0x4c0883...2318. The Safe
+	// address is what account creation derived for it before the proxy
+	// kinds were split out, so existing accounts keep resolving.
+	owner := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+
+	tests := []struct {
+		kind ProxyKind
+		want common.Address
+	}{
+		{ProxySafe, common.HexToAddress("0x907C14d6Cea8e8FC78dD3dB152F0a93f43276b4D")},
+	}
+
+	for _, tt := range tests {
+		got, err := Polygon.ProxyAddress(tt.kind, owner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s proxy of %s = %s, want %s", tt.kind, owner, got, tt.want)
+		}
+	}
+
+	if _, err := Polygon.ProxyAddress("unknown", owner); err == nil {
+		t.Error("unknown proxy kind was accepted")
+	}
+}
+
+func TestProxyAddressPublished(t *testing.T) {
+	// The Safe of a test owner as published in Polymarket's
+	// builder-relayer-client, derived there independently of this code.
+	owner := common.HexToAddress("0x6e0c80c90ea6c15917308F820Eac91Ce2724B5b5")
+	want := common.HexToAddress("0x6d8c4e9aDF5748Af82Dabe2C6225207770d6B4fa")
+
+	got, err := Polygon.ProxyAddress(ProxySafe, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("safe of %s = %s, want %s", owner, got, want)
+	}
+}
+
+func TestResolveProxies(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+
+	safe, err := Polygon.ProxyAddress(ProxySafe, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := Polygon.ProxyAddress(ProxyPolymarket, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		deployed []common.Address
+		want     *ProxyWallet
+	}{
+		{"none", nil, nil},
+		{"proxy", []common.Address{proxy}, &ProxyWallet{Kind: ProxyPolymarket, Address: proxy, Deployed: true}},
+		{"safe preferred", []common.Address{proxy, safe}, &ProxyWallet{Kind: ProxySafe, Address: safe, Deployed: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contracts := make(map[common.Address]*stubContract)
+			for _, address := range tt.deployed {
+				contracts[address] = newStubContract(t, safeABI)
+			}
+			wallet := newStubWalletWithKey(t, key, contracts)
+
+			proxies, err := wallet.ResolveProxies(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(proxies) != 2 || proxies[0].Address != safe || proxies[1].Address != proxy {
+				t.Fatalf("resolved %+v, want the safe %s and the proxy %s", proxies, safe, proxy)
+			}
+			for _, p := range proxies {
+				if want := slices.Contains(tt.deployed, p.Address); p.Deployed != want {
+					t.Errorf("%s deployed %v, want %v", p.Kind, p.Deployed, want)
+				}
+			}
+
+			deployed, err := wallet.DeployedProxy(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (deployed == nil) != (tt.want == nil) || deployed != nil && *deployed != *tt.want {
+				t.Errorf("deployed proxy %+v, want %+v", deployed, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectNetwork(t *testing.T) {
+	wallet, _ := newSimWallet(t)
+
+	// The simulated chain is 1337, where Polymarket has no contracts.
+	if err := wallet.SelectNetwork(context.Background()); err == nil {
+		t.Fatalf("selected %s for chain 1337", wallet.Network.Name)
+	}
+
+	network, err := NetworkByChainID(big.NewInt(137))
+	if err != nil || network != Polygon {
+		t.Errorf("chain 137 is %v (%v), want polygon", network, err)
+	}
+}
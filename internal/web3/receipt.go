@@ -0,0 +1,130 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// pollInterval is how often WaitMined and PendingTx.Wait look for receipts.
+var pollInterval = 2 * time.Second
+
+// TxResult is a mined transaction with the number of blocks on top of it.
+// Reorgs counts how many times the including block was replaced while
+// waiting.
+type TxResult struct {
+	Hash              common.Hash
+	Receipt           *types.Receipt
+	BlockNumber       uint64
+	BlockHash         common.Hash
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	Fee               *big.Int
+	Confirmations     uint64
+	Reorgs            int
+}
+
+func (r *TxResult) Success() bool {
+	return r.Receipt.Status == types.ReceiptStatusSuccessful
+}
+
+// WaitMined polls for the receipt of hash until it has the given number of
+// confirmations. If the including block is reorged out it goes back to
+// waiting for the transaction to be included again. A failed transaction is
+// returned together with ErrTxReverted.
+func (w *Wallet) WaitMined(ctx context.Context, hash common.Hash, confirmations uint64) (*TxResult, error) {
+	if confirmations == 0 {
+		confirmations = 1
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var (
+		seen   *types.Receipt
+		reorgs int
+	)
+
+	for {
+		result, err := w.checkMined(ctx, hash)
+		switch {
+		case err != nil:
+			log.Printf("error when check tx %s: %v", hash, err)
+		case result == nil:
+			if seen != nil {
+				reorgs++
+				log.Printf("Tx %s was reorged out of block %d", hash, seen.BlockNumber)
+				seen = nil
+			}
+		default:
+			if seen != nil && seen.BlockHash != result.BlockHash {
+				reorgs++
+				log.Printf("Tx %s moved from block %s to %s", hash, seen.BlockHash, result.BlockHash)
+			}
+			seen = result.Receipt
+			result.Reorgs = reorgs
+
+			if result.Confirmations >= confirmations {
+				if !result.Success() {
+					return result, fmt.Errorf("%w: %s", ErrTxReverted, hash)
+				}
+
+				return result, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error when wait tx %s: %w", hash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkMined returns nil without error if hash is not mined right now. The
+// node only serves receipts of canonical transactions, so a receipt that
+// disappears or changes block hash between polls means a reorg.
+func (w *Wallet) checkMined(ctx context.Context, hash common.Hash) (*TxResult, error) {
+	receipt, err := w.Client.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := w.Client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blockNumber := receipt.BlockNumber.Uint64()
+
+	var confirmations uint64
+	if head >= blockNumber {
+		confirmations = head - blockNumber + 1
+	}
+
+	effectivePrice := receipt.EffectiveGasPrice
+	if effectivePrice == nil {
+		effectivePrice = new(big.Int)
+	}
+
+	return &TxResult{
+		Hash:              hash,
+		Receipt:           receipt,
+		BlockNumber:       blockNumber,
+		BlockHash:         receipt.BlockHash,
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: effectivePrice,
+		Fee:               new(big.Int).Mul(effectivePrice, new(big.Int).SetUint64(receipt.GasUsed)),
+		Confirmations:     confirmations,
+	}, nil
+}
@@ -0,0 +1,156 @@
+package web3
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// scriptedChain runs the next step against the simulated chain every time
+// the receipt of trigger is looked up, so each poll of a wait loop sees
+// the chain one step further.
+type scriptedChain struct {
+	Backend
+	trigger common.Hash
+	steps   []func()
+}
+
+func (c *scriptedChain) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if hash == c.trigger && len(c.steps) > 0 {
+		step := c.steps[0]
+		c.steps = c.steps[1:]
+		step()
+	}
+
+	return c.Backend.TransactionReceipt(ctx, hash)
+}
+
+// reorgTo drops every block after parent and every pending tx, then
+// sends txs and mines them into a new block on top of parent.
+func reorgTo(t *testing.T, backend *simulated.Backend, parent common.Hash, txs ...*types.Transaction) {
+	t.Helper()
+
+	if err := backend.Fork(parent); err != nil {
+		t.Fatal(err)
+	}
+	// Let the pool put the reorged txs back before flushing them.
+	time.Sleep(50 * time.Millisecond)
+	backend.Rollback()
+
+	for _, tx := range txs {
+		if err := backend.Client().SendTransaction(context.Background(), tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backend.Commit()
+}
+
+func fastPolling(t *testing.T) {
+	interval := pollInterval
+	pollInterval = time.Millisecond
+	t.Cleanup(func() { pollInterval = interval })
+}
+
+func TestWaitMinedReorg(t *testing.T) {
+	fastPolling(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	wallet, backend := newSimWallet(t)
+	// Rollback leaves the pool's minimum tip at 1 gwei.
+	wallet.Gas = FixedGas{TipGwei: 1, MaxFeeGwei: 10}
+	parent := backend.Commit()
+
+	pending, err := wallet.SendTx(ctx, TxRequest{To: &common.Address{0x02}, Value: big.NewInt(1), GasLimit: 21000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := pending.Tx
+	backend.Commit()
+
+	wallet.Client = &scriptedChain{
+		Backend: wallet.Client,
+		trigger: tx.Hash(),
+		steps: []func(){
+			// Mined in block 2, one confirmation.
+			func() {},
+			// Block 2 is replaced by an empty one.
+			func() { reorgTo(t, backend, parent) },
+			// Mined again in block 3.
+			func() {
+				if err := backend.Client().SendTransaction(ctx, tx); err != nil {
+					t.Fatal(err)
+				}
+				backend.Commit()
+			},
+			func() { backend.Commit() },
+		},
+	}
+
+	result, err := wallet.WaitMined(ctx, tx.Hash(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.BlockNumber != 3 || result.Confirmations != 2 || result.Reorgs != 1 {
+		t.Errorf("mined in block %d with %d confirmations after %d reorgs, want block 3, 2 and 1",
+			result.BlockNumber, result.Confirmations, result.Reorgs)
+	}
+	if !result.Success() {
+		t.Error("tx reverted")
+	}
+}
+
+// TestPendingTxWaitReorgedReplacement has a reorg swap the mined
+// replacement for the tx it replaced; Wait must follow to the original.
+func TestPendingTxWaitReorgedReplacement(t *testing.T) {
+	fastPolling(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	wallet, backend := newSimWallet(t)
+	wallet.Gas = FixedGas{TipGwei: 1, MaxFeeGwei: 10}
+	parent := backend.Commit()
+
+	pending, err := wallet.SendTx(ctx, TxRequest{To: &common.Address{0x02}, Value: big.NewInt(1), GasLimit: 21000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := pending.Tx
+
+	if err := pending.bump(ctx, &BumpPolicy{After: time.Nanosecond}); err != nil {
+		t.Fatal(err)
+	}
+	replacement := pending.Tx
+	minedIn := backend.Commit()
+
+	wallet.Client = &scriptedChain{
+		Backend: wallet.Client,
+		trigger: replacement.Hash(),
+		steps: []func(){
+			// The replacement is mined in block 2.
+			func() {},
+			// A fork mines the original in block 2 instead.
+			func() { reorgTo(t, backend, parent, original) },
+			func() { backend.Commit() },
+		},
+	}
+
+	result, err := pending.Wait(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Hash != original.Hash() || pending.Tx != original {
+		t.Fatalf("mined %s, want the original %s", result.Hash, original.Hash())
+	}
+	if result.BlockNumber != 2 || result.BlockHash == minedIn || result.Confirmations != 2 || result.Reorgs != 1 {
+		t.Errorf("mined in block %d (%s) with %d confirmations after %d reorgs, want block 2 of the fork, 2 and 1",
+			result.BlockNumber, result.BlockHash, result.Confirmations, result.Reorgs)
+	}
+}
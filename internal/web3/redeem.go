@@ -0,0 +1,81 @@
+package web3
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// binaryOutcomes is the outcome count of every Polymarket condition.
+const binaryOutcomes = 2
+
+// RedeemData is the calldata redeeming the USDC.e collateralized positions
+// of indexSets in a condition of the root collection.
+func (c *ConditionalTokens) RedeemData(collateral common.Address, conditionID common.Hash, indexSets []*big.Int) ([]byte, error) {
+	return ctfABI.Pack("redeemPositions", collateral, common.Hash{}, conditionID, indexSets)
+}
+
+// RedeemCalls are the Safe calls redeeming every redeemable position, one
+// per condition, and the conditions they redeem. Neg-risk conditions are
+// redeemed through the adapter.
+func (w *Wallet) RedeemCalls(positions []PositionBalance) ([]MultiSendCall, []common.Hash, error) {
+	type condition struct {
+		negRisk   bool
+		indexSets []*big.Int
+		amounts   []*big.Int
+	}
+
+	var order []common.Hash
+	conditions := make(map[common.Hash]*condition)
+
+	for _, position := range positions {
+		if !position.Redeemable {
+			continue
+		}
+
+		cond, ok := conditions[position.ConditionID]
+		if !ok {
+			cond = &condition{negRisk: position.NegRisk, amounts: make([]*big.Int, binaryOutcomes)}
+			for i := range cond.amounts {
+				cond.amounts[i] = new(big.Int)
+			}
+			conditions[position.ConditionID] = cond
+			order = append(order, position.ConditionID)
+		}
+
+		if cond.negRisk != position.NegRisk {
+			return nil, nil, fmt.Errorf("condition %s has both neg-risk and plain positions", position.ConditionID)
+		}
+
+		outcome := position.IndexSet.BitLen() - 1
+		if outcome < 0 || outcome >= binaryOutcomes || position.IndexSet.Cmp(new(big.Int).Lsh(big.NewInt(1), uint(outcome))) != 0 {
+			return nil, nil, fmt.Errorf("position %s: index set %s is not a single outcome", position.ID, position.IndexSet)
+		}
+
+		cond.indexSets = append(cond.indexSets, position.IndexSet)
+		cond.amounts[outcome].Add(cond.amounts[outcome], position.Balance.Raw)
+	}
+
+	ctf := w.CTF()
+	calls := make([]MultiSendCall, 0, len(order))
+
+	for _, conditionID := range order {
+		cond := conditions[conditionID]
+
+		var err error
+		call := MultiSendCall{To: w.Network.Splitter(cond.negRisk)}
+		if cond.negRisk {
+			call.Data, err = NegRiskRedeemData(conditionID, cond.amounts)
+		} else {
+			call.Data, err = ctf.RedeemData(w.Network.USDC, conditionID, cond.indexSets)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		calls = append(calls, call)
+	}
+
+	return calls, order, nil
+}
@@ -0,0 +1,62 @@
+package web3
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRedeemCalls(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet := NewWithBackend(nil, NewKeySigner(key))
+
+	plain := common.HexToHash("0xc1")
+	negRisk := common.HexToHash("0xc2")
+	open := common.HexToHash("0xc3")
+
+	balance := func(units int64) Amount { return NewAmount(big.NewInt(units), USDCDecimals) }
+	positions := []PositionBalance{
+		{Position: Position{ID: big.NewInt(1), ConditionID: plain, IndexSet: big.NewInt(1)}, Balance: balance(2_000_000), Resolved: true, Redeemable: true},
+		// Unresolved and losing positions are left alone.
+		{Position: Position{ID: big.NewInt(3), ConditionID: open, IndexSet: big.NewInt(1)}, Balance: balance(5_000_000)},
+		{Position: Position{ID: big.NewInt(2), ConditionID: plain, IndexSet: big.NewInt(2)}, Balance: balance(1_000_000), Resolved: true},
+		{Position: Position{ID: big.NewInt(4), ConditionID: negRisk, IndexSet: big.NewInt(2), NegRisk: true}, Balance: balance(3_000_000), Resolved: true, Redeemable: true},
+	}
+
+	calls, conditions, err := wallet.RedeemCalls(positions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(conditions) != 2 || conditions[0] != plain || conditions[1] != negRisk {
+		t.Fatalf("conditions %v, want %s and %s", conditions, plain, negRisk)
+	}
+
+	redeem, err := wallet.CTF().RedeemData(Polygon.USDC, plain, []*big.Int{big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	adapterRedeem, err := NegRiskRedeemData(negRisk, []*big.Int{big.NewInt(0), big.NewInt(3_000_000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 2 ||
+		calls[0].To != Polygon.ConditionalTokens || !bytes.Equal(calls[0].Data, redeem) ||
+		calls[1].To != Polygon.NegRiskAdapter || !bytes.Equal(calls[1].Data, adapterRedeem) {
+		t.Errorf("calls %+v, want a CTF redeem of %s and an adapter redeem of %s", calls, plain, negRisk)
+	}
+
+	mixed := append(positions, PositionBalance{
+		Position: Position{ID: big.NewInt(5), ConditionID: plain, IndexSet: big.NewInt(2), NegRisk: true}, Resolved: true, Redeemable: true, Balance: balance(1),
+	})
+	if _, _, err := wallet.RedeemCalls(mixed); err == nil {
+		t.Error("redeemed a condition with neg-risk and plain positions")
+	}
+}
@@ -0,0 +1,194 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Safe operations: a plain call or a delegatecall from the Safe.
+const (
+	SafeCall         uint8 = 0
+	SafeDelegateCall uint8 = 1
+)
+
+// safeRelayedV is added to V of an owner's personal_sign of a SafeTx hash,
+// telling the Safe to check it as an eth_sign signature.
+const safeRelayedV = 4
+
+var safeABI = mustABI(`[
+	{"name":"nonce","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"execTransaction","type":"function","stateMutability":"payable","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},{"name":"signatures","type":"bytes"}],"outputs":[{"name":"success","type":"bool"}]}
+]`)
+
+// SafeTx is the EIP-712 message a Gnosis Safe owner signs to have the Safe
+// execute a call.
+type SafeTx struct {
+	To             common.Address `eip712:"to,address"`
+	Value          *big.Int       `eip712:"value,uint256"`
+	Data           []byte         `eip712:"data,bytes"`
+	Operation      uint8          `eip712:"operation,uint8"`
+	SafeTxGas      *big.Int       `eip712:"safeTxGas,uint256"`
+	BaseGas        *big.Int       `eip712:"baseGas,uint256"`
+	GasPrice       *big.Int       `eip712:"gasPrice,uint256"`
+	GasToken       common.Address `eip712:"gasToken,address"`
+	RefundReceiver common.Address `eip712:"refundReceiver,address"`
+	Nonce          *big.Int       `eip712:"nonce,uint256"`
+}
+
+// NewSafeTx is a SafeTx without gas refund, paid by whoever submits it.
+func NewSafeTx(to common.Address, value *big.Int, data []byte, operation uint8, nonce *big.Int) *SafeTx {
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	return &SafeTx{
+		To:        to,
+		Value:     value,
+		Data:      data,
+		Operation: operation,
+		SafeTxGas: new(big.Int),
+		BaseGas:   new(big.Int),
+		GasPrice:  new(big.Int),
+		Nonce:     nonce,
+	}
+}
+
+// SafeDomain is the EIP-712 domain of the Safe at safe (v1.3).
+func SafeDomain(chainID *big.Int, safe common.Address) Domain {
+	return Domain{
+		ChainID:           chainID,
+		VerifyingContract: safe,
+	}
+}
+
+// Safe is the wallet's Safe proxy, owned by the wallet's key alone.
+type Safe struct {
+	Address common.Address
+
+	wallet *Wallet
+}
+
+func (w *Wallet) Safe() (*Safe, error) {
+	address, err := w.ProxyAddress(ProxySafe)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Safe{Address: address, wallet: w}, nil
+}
+
+// Nonce is the nonce the next executed SafeTx must carry.
+func (s *Safe) Nonce(ctx context.Context) (*big.Int, error) {
+	out, err := s.wallet.call(ctx, safeABI, s.Address, "nonce")
+	if err != nil {
+		return nil, err
+	}
+
+	return out[0].(*big.Int), nil
+}
+
+func (s *Safe) typedData(ctx context.Context, tx *SafeTx) (apitypes.TypedData, error) {
+	if tx.Operation == SafeDelegateCall {
+		if err := s.wallet.Network.CheckDelegateCall(tx.To); err != nil {
+			return apitypes.TypedData{}, err
+		}
+	}
+
+	if err := s.wallet.CheckNetwork(ctx); err != nil {
+		return apitypes.TypedData{}, err
+	}
+
+	return NewTypedData(SafeDomain(s.wallet.Network.ChainID, s.Address), "SafeTx", tx)
+}
+
+// Hash is the EIP-712 hash of tx the owner signs.
+func (s *Safe) Hash(ctx context.Context, tx *SafeTx) (common.Hash, error) {
+	data, err := s.typedData(ctx, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return TypedDataHash(data)
+}
+
+// Sign signs tx as EIP-712 typed data, the signature execTransaction takes
+// from an owner.
+func (s *Safe) Sign(ctx context.Context, tx *SafeTx) ([]byte, error) {
+	data, err := s.typedData(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.wallet.Signer.SignTypedData(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifyTypedData(s.wallet.Address, data, signature); err != nil {
+		return nil, fmt.Errorf("signature does not verify: %w", err)
+	}
+
+	return signature, nil
+}
+
+// SafeTxSigner is a Signer that personal_signs Safe transaction hashes
+// only after seeing the transaction, like RemoteSigner.
+type SafeTxSigner interface {
+	SignSafeTx(ctx context.Context, chainID *big.Int, tx *SafeTx) ([]byte, error)
+}
+
+// SignRelayed personal_signs the hash of tx, the form Polymarket's relayer
+// accepts. V is shifted to 31/32 so the Safe verifies it as eth_sign.
+func (s *Safe) SignRelayed(ctx context.Context, tx *SafeTx) ([]byte, error) {
+	hash, err := s.Hash(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var signature []byte
+	if signer, ok := s.wallet.Signer.(SafeTxSigner); ok {
+		signature, err = signer.SignSafeTx(ctx, s.wallet.Network.ChainID, tx)
+	} else {
+		signature, err = s.wallet.Signer.SignPersonal(ctx, hash.Bytes())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifyPersonal(s.wallet.Address, hash.Bytes(), signature); err != nil {
+		return nil, fmt.Errorf("signature does not verify: %w", err)
+	}
+
+	signature[64] += safeRelayedV
+
+	return signature, nil
+}
+
+// Exec submits tx with signature to the Safe from the wallet's EOA, which
+// pays the gas.
+func (s *Safe) Exec(ctx context.Context, tx *SafeTx, signature []byte) (*PendingTx, error) {
+	return s.wallet.transact(ctx, safeABI, s.Address, "execTransaction",
+		tx.To, tx.Value, tx.Data, tx.Operation, tx.SafeTxGas, tx.BaseGas, tx.GasPrice, tx.GasToken, tx.RefundReceiver, signature)
+}
+
+// Execute has the Safe call to with data at its current nonce, signed and
+// submitted by the wallet.
+func (s *Safe) Execute(ctx context.Context, to common.Address, value *big.Int, data []byte, operation uint8) (*PendingTx, error) {
+	nonce, err := s.Nonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := NewSafeTx(to, value, data, operation, nonce)
+
+	signature, err := s.Sign(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Exec(ctx, tx, signature)
+}
@@ -0,0 +1,81 @@
+package web3
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer holds the key of a Wallet. KeySigner keeps it in memory,
+// RemoteSigner asks a separate signing service for every signature. Message
+// signatures are 65 bytes with V as 27/28.
+type Signer interface {
+	Address() common.Address
+	// SignPersonal signs data with the personal_sign prefix.
+	SignPersonal(ctx context.Context, data []byte) ([]byte, error)
+	// SignTypedData signs the EIP-712 hash of data.
+	SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error)
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignPersonal(_ context.Context, data []byte) ([]byte, error) {
+	signature, err := crypto.Sign(personalHash(data), s.key)
+	if err != nil {
+		return nil, fmt.Errorf("error when sign signature: %w", err)
+	}
+	signature[64] += 27
+
+	return signature, nil
+}
+
+func (s *KeySigner) SignTypedData(_ context.Context, data apitypes.TypedData) ([]byte, error) {
+	challenge, err := typedDataHash(data)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := crypto.Sign(challenge, s.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+	signature[64] += 27
+
+	return signature, nil
+}
+
+func (s *KeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), s.key)
+	if err != nil {
+		return nil, fmt.Errorf("error when sign tx: %w", err)
+	}
+
+	return signedTx, nil
+}
+
+func personalHash(data []byte) []byte {
+	msg := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)
+
+	return crypto.Keccak256([]byte(msg))
+}
@@ -0,0 +1,392 @@
+package web3
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// accountPolicy is what a signer server signs for one account: calls that
+// only move the account's funds between the EOA, its Safe and Payees, or
+// into Polymarket's contracts, and the typed data trading needs.
+type accountPolicy struct {
+	network *Network
+	owner   common.Address
+	safe    common.Address
+	proxy   common.Address
+	payees  []common.Address
+}
+
+func newAccountPolicy(network *Network, owner common.Address, payees []common.Address) (*accountPolicy, error) {
+	safe, err := network.ProxyAddress(ProxySafe, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := network.ProxyAddress(ProxyPolymarket, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountPolicy{network: network, owner: owner, safe: safe, proxy: proxy, payees: payees}, nil
+}
+
+// checkTx checks a transaction the EOA sends.
+func (p *accountPolicy) checkTx(to *common.Address, value *big.Int, data []byte) error {
+	if to == nil {
+		return fmt.Errorf("refusing to sign a contract creation")
+	}
+	if value != nil && value.Sign() != 0 {
+		return fmt.Errorf("refusing to send %s wei to %s", value, to)
+	}
+
+	return p.checkCall(p.owner, *to, data)
+}
+
+// checkCall checks a call from the EOA or the Safe.
+func (p *accountPolicy) checkCall(from, to common.Address, data []byte) error {
+	var err error
+
+	switch to {
+	case from:
+		// Empty transfers to self fill and cancel nonces.
+		if len(data) == 0 {
+			return nil
+		}
+		err = fmt.Errorf("call with data to itself")
+	case p.network.USDC:
+		err = p.checkUSDCCall(from, data)
+	case p.network.ConditionalTokens:
+		err = p.checkCTFCall(data)
+	case p.network.NegRiskAdapter:
+		_, _, err = decodeCall(negRiskAdapterABI, data, "splitPosition", "mergePositions", "redeemPositions", "convertPositions")
+	case p.safe:
+		if from != p.owner {
+			err = fmt.Errorf("call from %s", from)
+			break
+		}
+		err = p.checkExecTransaction(data)
+	default:
+		err = fmt.Errorf("unknown contract")
+	}
+	if err != nil {
+		return fmt.Errorf("refusing call %s to %s: %w", selectorOf(data), to, err)
+	}
+
+	return nil
+}
+
+func (p *accountPolicy) checkUSDCCall(from common.Address, data []byte) error {
+	method, args, err := decodeCall(erc20ABI, data, "approve", "transfer")
+	if err != nil {
+		return err
+	}
+
+	to := args[0].(common.Address)
+
+	switch method {
+	case "approve":
+		spenders := []common.Address{p.network.ConditionalTokens, p.network.CTFExchange, p.network.NegRiskExchange, p.network.NegRiskAdapter}
+		if !slices.Contains(spenders, to) {
+			return fmt.Errorf("approve of %s", to)
+		}
+	case "transfer":
+		if to == from || to != p.owner && to != p.safe && !slices.Contains(p.payees, to) {
+			return fmt.Errorf("transfer to %s", to)
+		}
+	}
+
+	return nil
+}
+
+func (p *accountPolicy) checkCTFCall(data []byte) error {
+	method, args, err := decodeCall(ctfABI, data, "setApprovalForAll", "splitPosition", "mergePositions", "redeemPositions")
+	if err != nil {
+		return err
+	}
+
+	if method == "setApprovalForAll" {
+		operator := args[0].(common.Address)
+		if !slices.Contains([]common.Address{p.network.CTFExchange, p.network.NegRiskExchange, p.network.NegRiskAdapter}, operator) {
+			return fmt.Errorf("approval for all to %s", operator)
+		}
+		return nil
+	}
+
+	if collateral := args[0].(common.Address); collateral != p.network.USDC {
+		return fmt.Errorf("collateral %s", collateral)
+	}
+
+	return nil
+}
+
+func (p *accountPolicy) checkExecTransaction(data []byte) error {
+	_, args, err := decodeCall(safeABI, data, "execTransaction")
+	if err != nil {
+		return err
+	}
+
+	return p.checkSafeTx(&SafeTx{
+		To:             args[0].(common.Address),
+		Value:          args[1].(*big.Int),
+		Data:           args[2].([]byte),
+		Operation:      args[3].(uint8),
+		SafeTxGas:      args[4].(*big.Int),
+		BaseGas:        args[5].(*big.Int),
+		GasPrice:       args[6].(*big.Int),
+		GasToken:       args[7].(common.Address),
+		RefundReceiver: args[8].(common.Address),
+	})
+}
+
+// checkSafeTx checks a transaction the Safe executes: a call passing
+// checkCall or a MultiSendCallOnly batch of them, without value or gas
+// refunds.
+func (p *accountPolicy) checkSafeTx(tx *SafeTx) error {
+	if tx.Value != nil && tx.Value.Sign() != 0 {
+		return fmt.Errorf("safe tx sends %s wei", tx.Value)
+	}
+	if tx.GasPrice != nil && tx.GasPrice.Sign() != 0 || tx.GasToken != (common.Address{}) || tx.RefundReceiver != (common.Address{}) {
+		return fmt.Errorf("safe tx pays a gas refund")
+	}
+
+	switch tx.Operation {
+	case SafeCall:
+		return p.checkCall(p.safe, tx.To, tx.Data)
+	case SafeDelegateCall:
+		if err := p.network.CheckDelegateCall(tx.To); err != nil {
+			return err
+		}
+
+		calls, err := DecodeMultiSend(tx.Data)
+		if err != nil {
+			return err
+		}
+		for i, call := range calls {
+			if call.Value.Sign() != 0 {
+				return fmt.Errorf("batch call %d sends %s wei", i, call.Value)
+			}
+			if err := p.checkCall(p.safe, call.To, call.Data); err != nil {
+				return fmt.Errorf("batch call %d: %w", i, err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("safe tx operation %d", tx.Operation)
+	}
+}
+
+// typedDataTypes are the messages signed for trading, with their fields.
+var typedDataTypes = map[string][]apitypes.Type{
+	"ClobAuth": {
+		{Name: "address", Type: "address"},
+		{Name: "timestamp", Type: "string"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "message", Type: "string"},
+	},
+	"CreateProxy": {
+		{Name: "paymentToken", Type: "address"},
+		{Name: "payment", Type: "uint256"},
+		{Name: "paymentReceiver", Type: "address"},
+	},
+	"Order": {
+		{Name: "salt", Type: "uint256"},
+		{Name: "maker", Type: "address"},
+		{Name: "signer", Type: "address"},
+		{Name: "taker", Type: "address"},
+		{Name: "tokenId", Type: "uint256"},
+		{Name: "makerAmount", Type: "uint256"},
+		{Name: "takerAmount", Type: "uint256"},
+		{Name: "expiration", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "feeRateBps", Type: "uint256"},
+		{Name: "side", Type: "uint8"},
+		{Name: "signatureType", Type: "uint8"},
+	},
+	"SafeTx": {
+		{Name: "to", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "data", Type: "bytes"},
+		{Name: "operation", Type: "uint8"},
+		{Name: "safeTxGas", Type: "uint256"},
+		{Name: "baseGas", Type: "uint256"},
+		{Name: "gasPrice", Type: "uint256"},
+		{Name: "gasToken", Type: "address"},
+		{Name: "refundReceiver", Type: "address"},
+		{Name: "nonce", Type: "uint256"},
+	},
+}
+
+// checkTypedData allows CLOB auth, account creation without payment, orders
+// of the account, and Safe transactions passing checkSafeTx. Orders are not
+// priced here: whoever can sign them can trade the account's positions.
+func (p *accountPolicy) checkTypedData(data apitypes.TypedData) error {
+	want, ok := typedDataTypes[data.PrimaryType]
+	if !ok || len(data.Types) != 2 || !slices.Equal(data.Types[data.PrimaryType], want) {
+		return fmt.Errorf("refusing to sign typed data %q", data.PrimaryType)
+	}
+
+	chainID := (*big.Int)(data.Domain.ChainId)
+	if chainID == nil || chainID.Cmp(p.network.ChainID) != 0 {
+		return fmt.Errorf("refusing to sign typed data for chain %v", chainID)
+	}
+
+	domain := Domain{Name: data.Domain.Name, Version: data.Domain.Version, ChainID: chainID}
+	if data.Domain.VerifyingContract != "" {
+		if !common.IsHexAddress(data.Domain.VerifyingContract) {
+			return fmt.Errorf("invalid verifying contract %q", data.Domain.VerifyingContract)
+		}
+		domain.VerifyingContract = common.HexToAddress(data.Domain.VerifyingContract)
+	}
+	if data.Domain.Salt != "" || !slices.Equal(data.Types["EIP712Domain"], domain.types()) {
+		return fmt.Errorf("refusing to sign typed data with a non-standard domain")
+	}
+
+	msg := data.Message
+
+	switch {
+	case data.PrimaryType == "ClobAuth" && domain == Domain{Name: "ClobAuthDomain", Version: "1", ChainID: chainID}:
+		if address, err := messageAddress(msg, "address"); err != nil || address != p.owner {
+			return fmt.Errorf("refusing CLOB auth for %v", msg["address"])
+		}
+	case data.PrimaryType == "CreateProxy" && domain == Domain{Name: "Polymarket Contract Proxy Factory", ChainID: chainID, VerifyingContract: p.network.SafeFactory}:
+		if payment, err := messageBig(msg, "payment"); err != nil || payment.Sign() != 0 {
+			return fmt.Errorf("refusing account creation with payment %v", msg["payment"])
+		}
+	case data.PrimaryType == "Order" && domain.Name == "Polymarket CTF Exchange" && domain.Version == "1" &&
+		(domain.VerifyingContract == p.network.CTFExchange || domain.VerifyingContract == p.network.NegRiskExchange):
+		signer, err := messageAddress(msg, "signer")
+		if err != nil || signer != p.owner {
+			return fmt.Errorf("refusing order signed for %v", msg["signer"])
+		}
+		maker, err := messageAddress(msg, "maker")
+		if err != nil || maker != p.owner && maker != p.safe && maker != p.proxy {
+			return fmt.Errorf("refusing order of maker %v", msg["maker"])
+		}
+	case data.PrimaryType == "SafeTx" && domain == Domain{ChainID: chainID, VerifyingContract: p.safe}:
+		tx, err := safeTxFromMessage(msg)
+		if err != nil {
+			return err
+		}
+		canonical, err := NewTypedData(SafeDomain(chainID, p.safe), "SafeTx", tx)
+		if err != nil {
+			return err
+		}
+		hash, err := TypedDataHash(data)
+		if err != nil {
+			return err
+		}
+		if want, err := TypedDataHash(canonical); err != nil || hash != want {
+			return fmt.Errorf("refusing a safe tx that does not hash as sent")
+		}
+		return p.checkSafeTx(tx)
+	default:
+		return fmt.Errorf("refusing to sign %s for domain %q at %s", data.PrimaryType, domain.Name, domain.VerifyingContract)
+	}
+
+	return nil
+}
+
+// checkMessage allows Sign-In with Ethereum to Polymarket as the account.
+// Bare 32 byte hashes are refused, as they could be any Safe transaction;
+// those are signed through eth_signSafeTx.
+func (p *accountPolicy) checkMessage(data []byte) error {
+	prefix := fmt.Sprintf("polymarket.com wants you to sign in with your Ethereum account:\n%s\n", p.owner.Hex())
+	msg := string(data)
+
+	if len(data) == common.HashLength || !strings.HasPrefix(msg, prefix) ||
+		!strings.Contains(msg, "\nURI: https://polymarket.com\n") ||
+		!strings.Contains(msg, fmt.Sprintf("\nChain ID: %s\n", p.network.ChainID)) {
+		return fmt.Errorf("refusing to sign a message other than a Polymarket sign-in")
+	}
+
+	return nil
+}
+
+// decodeCall unpacks calldata of one of the allowed methods of contractABI.
+func decodeCall(contractABI abi.ABI, data []byte, allowed ...string) (string, []any, error) {
+	if len(data) < 4 {
+		return "", nil, fmt.Errorf("no method selector")
+	}
+
+	method, err := contractABI.MethodById(data[:4])
+	if err != nil || !slices.Contains(allowed, method.Name) {
+		return "", nil, fmt.Errorf("method not allowed")
+	}
+
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to unpack %s: %w", method.Name, err)
+	}
+
+	return method.Name, args, nil
+}
+
+func selectorOf(data []byte) string {
+	if len(data) < 4 {
+		return "0x"
+	}
+
+	return hexutil.Encode(data[:4])
+}
+
+func messageAddress(msg apitypes.TypedDataMessage, key string) (common.Address, error) {
+	s, ok := msg[key].(string)
+	if !ok || !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("invalid %s %v", key, msg[key])
+	}
+
+	return common.HexToAddress(s), nil
+}
+
+func messageBig(msg apitypes.TypedDataMessage, key string) (*big.Int, error) {
+	s, ok := msg[key].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s %v", key, msg[key])
+	}
+
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("invalid %s %q", key, s)
+	}
+
+	return n, nil
+}
+
+func safeTxFromMessage(msg apitypes.TypedDataMessage) (*SafeTx, error) {
+	tx := new(SafeTx)
+
+	var err error
+	for key, dst := range map[string]*common.Address{"to": &tx.To, "gasToken": &tx.GasToken, "refundReceiver": &tx.RefundReceiver} {
+		if *dst, err = messageAddress(msg, key); err != nil {
+			return nil, err
+		}
+	}
+	for key, dst := range map[string]**big.Int{"value": &tx.Value, "safeTxGas": &tx.SafeTxGas, "baseGas": &tx.BaseGas, "gasPrice": &tx.GasPrice, "nonce": &tx.Nonce} {
+		if *dst, err = messageBig(msg, key); err != nil {
+			return nil, err
+		}
+	}
+
+	operation, err := messageBig(msg, "operation")
+	if err != nil || !operation.IsUint64() || operation.Uint64() > 255 {
+		return nil, fmt.Errorf("invalid operation %v", msg["operation"])
+	}
+	tx.Operation = uint8(operation.Uint64())
+
+	s, ok := msg["data"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid data %v", msg["data"])
+	}
+	if tx.Data, err = hexutil.Decode(s); err != nil {
+		return nil, fmt.Errorf("invalid data: %w", err)
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,223 @@
+package web3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// RemoteSigner signs through a Web3Signer/Clef-style JSON-RPC service
+// (eth_sign, eth_signTypedData, eth_signTransaction), so the key never
+// enters this process.
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// signTxArgs is the transaction object accepted by eth_signTransaction.
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+// safeTxArgs is the Safe transaction accepted by eth_signSafeTx.
+type safeTxArgs struct {
+	To             common.Address `json:"to"`
+	Value          *hexutil.Big   `json:"value"`
+	Data           hexutil.Bytes  `json:"data"`
+	Operation      hexutil.Uint64 `json:"operation"`
+	SafeTxGas      *hexutil.Big   `json:"safeTxGas"`
+	BaseGas        *hexutil.Big   `json:"baseGas"`
+	GasPrice       *hexutil.Big   `json:"gasPrice"`
+	GasToken       common.Address `json:"gasToken"`
+	RefundReceiver common.Address `json:"refundReceiver"`
+	Nonce          *hexutil.Big   `json:"nonce"`
+}
+
+func newSafeTxArgs(tx *SafeTx) safeTxArgs {
+	return safeTxArgs{
+		To:             tx.To,
+		Value:          (*hexutil.Big)(tx.Value),
+		Data:           tx.Data,
+		Operation:      hexutil.Uint64(tx.Operation),
+		SafeTxGas:      (*hexutil.Big)(tx.SafeTxGas),
+		BaseGas:        (*hexutil.Big)(tx.BaseGas),
+		GasPrice:       (*hexutil.Big)(tx.GasPrice),
+		GasToken:       tx.GasToken,
+		RefundReceiver: tx.RefundReceiver,
+		Nonce:          (*hexutil.Big)(tx.Nonce),
+	}
+}
+
+func (a safeTxArgs) safeTx() *SafeTx {
+	return &SafeTx{
+		To:             a.To,
+		Value:          bigOrZero(a.Value),
+		Data:           a.Data,
+		Operation:      uint8(min(a.Operation, 255)),
+		SafeTxGas:      bigOrZero(a.SafeTxGas),
+		BaseGas:        bigOrZero(a.BaseGas),
+		GasPrice:       bigOrZero(a.GasPrice),
+		GasToken:       a.GasToken,
+		RefundReceiver: a.RefundReceiver,
+		Nonce:          bigOrZero(a.Nonce),
+	}
+}
+
+func NewRemoteSigner(client *rpc.Client, address common.Address) *RemoteSigner {
+	return &RemoteSigner{
+		client:  client,
+		address: address,
+	}
+}
+
+// DialSigner connects to a signing service, authenticating with token as a
+// bearer token, and returns a signer for every account it exposes through
+// eth_accounts.
+func DialSigner(ctx context.Context, url, token string) ([]*RemoteSigner, error) {
+	var options []rpc.ClientOption
+	if token != "" {
+		options = append(options, rpc.WithHeader("Authorization", "Bearer "+token))
+	}
+
+	client, err := rpc.DialOptions(ctx, url, options...)
+	if err != nil {
+		return nil, fmt.Errorf("can't connect to signer: %w", err)
+	}
+
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error when get signer accounts: %w", err)
+	}
+
+	if len(accounts) == 0 {
+		client.Close()
+		return nil, errors.New("signer has no accounts")
+	}
+
+	signers := make([]*RemoteSigner, 0, len(accounts))
+	for _, account := range accounts {
+		signers = append(signers, NewRemoteSigner(client, account))
+	}
+
+	return signers, nil
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *RemoteSigner) SignPersonal(ctx context.Context, data []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	if err := s.client.CallContext(ctx, &signature, "eth_sign", s.address, hexutil.Bytes(data)); err != nil {
+		return nil, fmt.Errorf("error when remote sign message: %w", err)
+	}
+
+	return NormalizeV(signature)
+}
+
+// SignSafeTx personal_signs the hash of tx for the account's Safe through
+// eth_signSafeTx, so the service can check what the hash stands for.
+func (s *RemoteSigner) SignSafeTx(ctx context.Context, chainID *big.Int, tx *SafeTx) ([]byte, error) {
+	var signature hexutil.Bytes
+	if err := s.client.CallContext(ctx, &signature, "eth_signSafeTx", s.address, (*hexutil.Big)(chainID), newSafeTxArgs(tx)); err != nil {
+		return nil, fmt.Errorf("error when remote sign safe tx: %w", err)
+	}
+
+	return NormalizeV(signature)
+}
+
+func (s *RemoteSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	var signature hexutil.Bytes
+	if err := s.client.CallContext(ctx, &signature, "eth_signTypedData", s.address, data); err != nil {
+		return nil, fmt.Errorf("error when remote sign typed data: %w", err)
+	}
+
+	return NormalizeV(signature)
+}
+
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:    s.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var raw hexutil.Bytes
+	if err := s.client.CallContext(ctx, &raw, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("error when remote sign tx: %w", err)
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("can't decode remote signed tx: %w", err)
+	}
+
+	if err := checkSignedTx(tx, signedTx, chainID, s.address); err != nil {
+		return nil, err
+	}
+
+	return signedTx, nil
+}
+
+// checkSignedTx makes sure the signing service signed exactly the
+// transaction it was asked to sign, by the expected account.
+func checkSignedTx(tx, signedTx *types.Transaction, chainID *big.Int, from common.Address) error {
+	sender, err := types.Sender(types.NewLondonSigner(chainID), signedTx)
+	if err != nil {
+		return fmt.Errorf("can't recover remote signed tx sender: %w", err)
+	}
+
+	if sender != from {
+		return fmt.Errorf("remote signed tx from %s, expected %s", sender, from)
+	}
+
+	if signedTx.Type() != tx.Type() ||
+		signedTx.Nonce() != tx.Nonce() ||
+		signedTx.Gas() != tx.Gas() ||
+		signedTx.Value().Cmp(tx.Value()) != 0 ||
+		signedTx.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 ||
+		signedTx.GasTipCap().Cmp(tx.GasTipCap()) != 0 ||
+		!sameTo(signedTx.To(), tx.To()) ||
+		!bytes.Equal(signedTx.Data(), tx.Data()) {
+		return errors.New("remote signed tx does not match request")
+	}
+
+	return nil
+}
+
+func sameTo(a, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
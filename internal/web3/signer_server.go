@@ -0,0 +1,219 @@
+package web3
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// SignerPolicy limits what a signer server signs. Token must be sent as a
+// bearer token with every request. Transactions must be for Network's chain
+// and only move funds between an account, its Safe and Payees, approve
+// Polymarket's contracts or trade through them; calldata is checked per
+// contract and method, and Safe transactions by what they execute. Typed
+// data is limited to CLOB auth, account creation, the account's orders and
+// such Safe transactions. Personal messages are limited to the Polymarket
+// sign-in; hashes of Safe transactions are signed through eth_signSafeTx,
+// which rebuilds the hash from the transaction.
+type SignerPolicy struct {
+	Token   string
+	Network *Network
+	Payees  []common.Address
+}
+
+// signerService exposes local signers over the same eth_* methods
+// RemoteSigner calls.
+type signerService struct {
+	signers  map[common.Address]Signer
+	network  *Network
+	policies map[common.Address]*accountPolicy
+}
+
+// NewSignerServer returns a JSON-RPC handler that signs with the given
+// signers under policy. It is meant to run in its own process, see
+// cmd/signer.
+func NewSignerServer(policy SignerPolicy, signers ...Signer) (http.Handler, error) {
+	if policy.Token == "" {
+		return nil, errors.New("signer server needs an auth token")
+	}
+	if policy.Network == nil {
+		return nil, errors.New("signer server needs a network")
+	}
+
+	service := &signerService{
+		signers:  make(map[common.Address]Signer, len(signers)),
+		network:  policy.Network,
+		policies: make(map[common.Address]*accountPolicy, len(signers)),
+	}
+
+	for _, signer := range signers {
+		address := signer.Address()
+
+		accountPolicy, err := newAccountPolicy(policy.Network, address, policy.Payees)
+		if err != nil {
+			return nil, err
+		}
+
+		service.signers[address] = signer
+		service.policies[address] = accountPolicy
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		return nil, fmt.Errorf("can't register signer service: %w", err)
+	}
+
+	return requireToken(policy.Token, server), nil
+}
+
+// requireToken rejects requests without "Authorization: Bearer <token>".
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *signerService) signer(address common.Address) (Signer, *accountPolicy, error) {
+	signer, ok := s.signers[address]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown account %s", address)
+	}
+
+	return signer, s.policies[address], nil
+}
+
+func (s *signerService) Accounts() []common.Address {
+	accounts := make([]common.Address, 0, len(s.signers))
+	for address := range s.signers {
+		accounts = append(accounts, address)
+	}
+
+	return accounts
+}
+
+func (s *signerService) Sign(ctx context.Context, address common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	signer, policy, err := s.signer(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.checkMessage(data); err != nil {
+		return nil, err
+	}
+
+	return signer.SignPersonal(ctx, data)
+}
+
+// SignSafeTx personal_signs the hash of a Safe transaction of the account's
+// Safe, for the relayer, if the transaction passes the policy.
+func (s *signerService) SignSafeTx(ctx context.Context, address common.Address, chainID *hexutil.Big, args safeTxArgs) (hexutil.Bytes, error) {
+	signer, policy, err := s.signer(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if chainID == nil || chainID.ToInt().Cmp(s.network.ChainID) != 0 {
+		return nil, fmt.Errorf("refusing to sign a safe tx for chain %v", chainID)
+	}
+
+	tx := args.safeTx()
+	if err := policy.checkSafeTx(tx); err != nil {
+		return nil, err
+	}
+
+	data, err := NewTypedData(SafeDomain(chainID.ToInt(), policy.safe), "SafeTx", tx)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := TypedDataHash(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.SignPersonal(ctx, hash.Bytes())
+}
+
+func (s *signerService) SignTypedData(ctx context.Context, address common.Address, data apitypes.TypedData) (hexutil.Bytes, error) {
+	signer, policy, err := s.signer(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.checkTypedData(data); err != nil {
+		return nil, err
+	}
+
+	return signer.SignTypedData(ctx, data)
+}
+
+func (s *signerService) SignTransaction(ctx context.Context, args signTxArgs) (hexutil.Bytes, error) {
+	signer, policy, err := s.signer(args.From)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.ChainID == nil {
+		return nil, fmt.Errorf("chainId is required")
+	}
+	if args.ChainID.ToInt().Cmp(s.network.ChainID) != 0 {
+		return nil, fmt.Errorf("refusing to sign for chain %s", args.ChainID.ToInt())
+	}
+	if err := policy.checkTx(args.To, bigOrZero(args.Value), args.Data); err != nil {
+		return nil, err
+	}
+
+	var tx *types.Transaction
+	if args.MaxFeePerGas != nil {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: bigOrZero(args.MaxPriorityFeePerGas),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     bigOrZero(args.Value),
+			Data:      args.Data,
+		})
+	} else {
+		tx = types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: bigOrZero(args.GasPrice),
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    bigOrZero(args.Value),
+			Data:     args.Data,
+		})
+	}
+
+	signedTx, err := signer.SignTx(ctx, tx, args.ChainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+
+	return signedTx.MarshalBinary()
+}
+
+func bigOrZero(v *hexutil.Big) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+
+	return v.ToInt()
+}
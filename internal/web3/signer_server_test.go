@@ -0,0 +1,298 @@
+package web3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const testSignerToken = "0123456789abcdef0123456789abcdef"
+
+func newTestSignerServer(t *testing.T) (*KeySigner, string) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewKeySigner(key)
+
+	handler, err := NewSignerServer(SignerPolicy{Token: testSignerToken, Network: Polygon}, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return signer, server.URL
+}
+
+func dialTestSigner(t *testing.T, url string) *RemoteSigner {
+	t.Helper()
+
+	signers, err := DialSigner(context.Background(), url, testSignerToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signers) != 1 {
+		t.Fatalf("got %d accounts, want 1", len(signers))
+	}
+
+	return signers[0]
+}
+
+func TestSignerServerNeedsToken(t *testing.T) {
+	if _, err := NewSignerServer(SignerPolicy{Network: Polygon}); err == nil {
+		t.Fatal("server without token was created")
+	}
+
+	_, url := newTestSignerServer(t)
+
+	for _, token := range []string{"", "wrong"} {
+		if _, err := DialSigner(context.Background(), url, token); err == nil {
+			t.Errorf("token %q was accepted", token)
+		}
+	}
+}
+
+func TestRemoteSignerSignsMessages(t *testing.T) {
+	local, url := newTestSignerServer(t)
+	remote := dialTestSigner(t, url)
+	ctx := context.Background()
+
+	if remote.Address() != local.Address() {
+		t.Fatalf("remote account %s, want %s", remote.Address(), local.Address())
+	}
+
+	signIn := []byte(fmt.Sprintf("polymarket.com wants you to sign in with your Ethereum account:\n%s\n\nWelcome to Polymarket! Sign to connect.\n\nURI: https://polymarket.com\nVersion: 1\nChain ID: 137\nNonce: abc\n", local.Address()))
+	signature, err := remote.SignPersonal(ctx, signIn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPersonal(local.Address(), signIn, signature); err != nil {
+		t.Error(err)
+	}
+
+	otherSignIn := bytes.Replace(signIn, []byte(local.Address().Hex()), []byte(common.HexToAddress("0x1234").Hex()), 1)
+	for name, msg := range map[string][]byte{
+		"message":              []byte("hello"),
+		"hash":                 crypto.Keccak256([]byte("hello")),
+		"sign-in as another":   otherSignIn,
+		"sign-in to testnet":   bytes.Replace(signIn, []byte("Chain ID: 137"), []byte("Chain ID: 80002"), 1),
+		"sign-in to elsewhere": bytes.Replace(signIn, []byte("URI: https://polymarket.com"), []byte("URI: https://evil.com"), 1),
+	} {
+		if _, err := remote.SignPersonal(ctx, msg); err == nil {
+			t.Errorf("%s was signed", name)
+		}
+	}
+
+	auth := func(address common.Address, domain Domain) apitypes.TypedData {
+		data, err := NewTypedData(domain, "ClobAuth", testClobAuth{Address: address, Nonce: big.NewInt(0)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	clobDomain := Domain{Name: "ClobAuthDomain", Version: "1", ChainID: Polygon.ChainID}
+
+	data := auth(local.Address(), clobDomain)
+	signature, err = remote.SignTypedData(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyTypedData(local.Address(), data, signature); err != nil {
+		t.Error(err)
+	}
+
+	for name, data := range map[string]apitypes.TypedData{
+		"other account": auth(common.HexToAddress("0x1234"), clobDomain),
+		"other chain":   auth(local.Address(), Domain{Name: "ClobAuthDomain", Version: "1", ChainID: big.NewInt(1)}),
+		"other domain":  auth(local.Address(), Domain{Name: "test", ChainID: Polygon.ChainID}),
+	} {
+		if _, err := remote.SignTypedData(ctx, data); err == nil {
+			t.Errorf("typed data for %s was signed", name)
+		}
+	}
+}
+
+// testClobAuth mirrors the CLOB's ClobAuth message.
+type testClobAuth struct {
+	Address   common.Address `eip712:"address,address"`
+	Timestamp string         `eip712:"timestamp,string"`
+	Nonce     *big.Int       `eip712:"nonce,uint256"`
+	Message   string         `eip712:"message,string"`
+}
+
+func TestRemoteSignerTxPolicy(t *testing.T) {
+	local, url := newTestSignerServer(t)
+	remote := dialTestSigner(t, url)
+	ctx := context.Background()
+
+	safe, err := Polygon.ProxyAddress(ProxySafe, local.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	attacker := common.HexToAddress("0x1234")
+
+	pack := func(contractABI abi.ABI, method string, args ...any) []byte {
+		data, err := contractABI.Pack(method, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	amount := big.NewInt(1e6)
+
+	execSafe := func(to common.Address, data []byte, operation uint8) []byte {
+		return pack(safeABI, "execTransaction", to, new(big.Int), data, operation,
+			new(big.Int), new(big.Int), new(big.Int), common.Address{}, common.Address{}, []byte{})
+	}
+	batch := func(calls ...MultiSendCall) []byte {
+		to, data, err := Polygon.MultiSendTx(calls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return execSafe(to, data, SafeDelegateCall)
+	}
+
+	tests := []struct {
+		name    string
+		to      common.Address
+		value   int64
+		data    []byte
+		chainID *big.Int
+		ok      bool
+	}{
+		{"deposit to own safe", Polygon.USDC, 0, pack(erc20ABI, "transfer", safe, amount), Polygon.ChainID, true},
+		{"approve exchange", Polygon.USDC, 0, pack(erc20ABI, "approve", Polygon.CTFExchange, amount), Polygon.ChainID, true},
+		{"approve for all neg risk", Polygon.ConditionalTokens, 0, pack(ctfABI, "setApprovalForAll", Polygon.NegRiskExchange, true), Polygon.ChainID, true},
+		{"fill nonce", local.Address(), 0, nil, Polygon.ChainID, true},
+		{"safe withdraws to owner", safe, 0, execSafe(Polygon.USDC, pack(erc20ABI, "transfer", local.Address(), amount), SafeCall), Polygon.ChainID, true},
+		{"safe approves in batch", safe, 0, batch(
+			MultiSendCall{To: Polygon.USDC, Value: new(big.Int), Data: pack(erc20ABI, "approve", Polygon.NegRiskAdapter, amount)},
+			MultiSendCall{To: Polygon.ConditionalTokens, Value: new(big.Int), Data: pack(ctfABI, "setApprovalForAll", Polygon.CTFExchange, true)},
+		), Polygon.ChainID, true},
+
+		{"transfer to attacker", Polygon.USDC, 0, pack(erc20ABI, "transfer", attacker, amount), Polygon.ChainID, false},
+		{"approve attacker", Polygon.USDC, 0, pack(erc20ABI, "approve", attacker, amount), Polygon.ChainID, false},
+		{"approve for all to attacker", Polygon.ConditionalTokens, 0, pack(ctfABI, "setApprovalForAll", attacker, true), Polygon.ChainID, false},
+		{"usdc without call", Polygon.USDC, 0, nil, Polygon.ChainID, false},
+		{"send matic", attacker, 1, nil, Polygon.ChainID, false},
+		{"unknown target", attacker, 0, nil, Polygon.ChainID, false},
+		{"exchange", Polygon.CTFExchange, 0, nil, Polygon.ChainID, false},
+		{"safe sends to attacker", safe, 0, execSafe(Polygon.USDC, pack(erc20ABI, "transfer", attacker, amount), SafeCall), Polygon.ChainID, false},
+		{"safe delegatecalls attacker", safe, 0, execSafe(attacker, nil, SafeDelegateCall), Polygon.ChainID, false},
+		{"safe batch sends to attacker", safe, 0, batch(
+			MultiSendCall{To: Polygon.USDC, Value: new(big.Int), Data: pack(erc20ABI, "approve", Polygon.CTFExchange, amount)},
+			MultiSendCall{To: Polygon.USDC, Value: new(big.Int), Data: pack(erc20ABI, "transfer", attacker, amount)},
+		), Polygon.ChainID, false},
+		{"other chain", Polygon.USDC, 0, pack(erc20ABI, "transfer", safe, amount), big.NewInt(1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := tt.to
+			tx := types.NewTx(&types.DynamicFeeTx{
+				ChainID:   tt.chainID,
+				Nonce:     7,
+				GasTipCap: big.NewInt(30e9),
+				GasFeeCap: big.NewInt(100e9),
+				Gas:       100000,
+				To:        &to,
+				Value:     big.NewInt(tt.value),
+				Data:      tt.data,
+			})
+
+			signed, err := remote.SignTx(ctx, tx, tt.chainID)
+			if !tt.ok {
+				if err == nil {
+					t.Fatal("transaction was signed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sender, err := types.Sender(types.NewLondonSigner(tt.chainID), signed)
+			if err != nil || sender != local.Address() {
+				t.Fatalf("signed by %s (%v), want %s", sender, err, local.Address())
+			}
+		})
+	}
+}
+
+func TestRemoteSignerSafeTx(t *testing.T) {
+	local, url := newTestSignerServer(t)
+	remote := dialTestSigner(t, url)
+	ctx := context.Background()
+
+	safe, err := Polygon.ProxyAddress(ProxySafe, local.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfer := func(to common.Address) *SafeTx {
+		data, err := erc20ABI.Pack("transfer", to, big.NewInt(1e6))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewSafeTx(Polygon.USDC, nil, data, SafeCall, big.NewInt(3))
+	}
+	good, bad := transfer(local.Address()), transfer(common.HexToAddress("0x1234"))
+
+	typedData := func(tx *SafeTx) apitypes.TypedData {
+		data, err := NewTypedData(SafeDomain(Polygon.ChainID, safe), "SafeTx", tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	data := typedData(good)
+	signature, err := remote.SignTypedData(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyTypedData(local.Address(), data, signature); err != nil {
+		t.Error(err)
+	}
+	if _, err := remote.SignTypedData(ctx, typedData(bad)); err == nil {
+		t.Error("safe tx to an attacker was signed as typed data")
+	}
+
+	refund := *good
+	refund.GasPrice, refund.RefundReceiver = big.NewInt(1), common.HexToAddress("0x1234")
+	if _, err := remote.SignTypedData(ctx, typedData(&refund)); err == nil {
+		t.Error("safe tx paying a refund was signed")
+	}
+
+	hash, err := TypedDataHash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err = remote.SignSafeTx(ctx, Polygon.ChainID, good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPersonal(local.Address(), hash.Bytes(), signature); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := remote.SignSafeTx(ctx, Polygon.ChainID, bad); err == nil {
+		t.Error("safe tx to an attacker was signed through eth_signSafeTx")
+	}
+	if _, err := remote.SignSafeTx(ctx, big.NewInt(1), good); err == nil {
+		t.Error("safe tx for another chain was signed")
+	}
+}
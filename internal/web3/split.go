@@ -0,0 +1,166 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"polymarket/internal/ctfid"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// binaryPartition splits collateral into the two outcomes of a condition.
+var binaryPartition = []*big.Int{big.NewInt(1), big.NewInt(2)}
+
+// OutcomePositions are the YES and NO positions of a binary condition.
+func (n *Network) OutcomePositions(conditionID common.Hash, negRisk bool) ([]Position, error) {
+	collateral := n.USDC
+	if negRisk {
+		collateral = n.NegRiskCollateral
+	}
+
+	positions := make([]Position, len(binaryPartition))
+	for i, indexSet := range binaryPartition {
+		collectionID, err := ctfid.CollectionID(common.Hash{}, conditionID, indexSet)
+		if err != nil {
+			return nil, err
+		}
+
+		positions[i] = Position{
+			ID:          ctfid.PositionID(collateral, collectionID),
+			ConditionID: conditionID,
+			IndexSet:    indexSet,
+			NegRisk:     negRisk,
+		}
+	}
+
+	return positions, nil
+}
+
+// SplitCalls are the Safe calls turning amount of USDC.e into amount of
+// both outcomes of conditionID, approving the splitting contract first if
+// needed. Neg-risk conditions are split through the adapter.
+func (s *Safe) SplitCalls(ctx context.Context, conditionID common.Hash, amount Amount, negRisk bool) ([]MultiSendCall, error) {
+	if err := s.checkCondition(ctx, conditionID, amount); err != nil {
+		return nil, err
+	}
+
+	usdc := s.wallet.USDC()
+	if err := usdc.checkBalance(ctx, s.Address, amount); err != nil {
+		return nil, err
+	}
+
+	spender := s.wallet.Network.Splitter(negRisk)
+
+	var calls []MultiSendCall
+
+	approved, err := usdc.HasAllowance(ctx, s.Address, spender, amount)
+	if err != nil {
+		return nil, err
+	}
+	if !approved {
+		data, err := usdc.ApproveData(spender, amount)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, MultiSendCall{To: usdc.Address, Data: data})
+	}
+
+	var data []byte
+	if negRisk {
+		data, err = negRiskAdapterABI.Pack("splitPosition", conditionID, amount.Raw)
+	} else {
+		data, err = ctfABI.Pack("splitPosition", usdc.Address, common.Hash{}, conditionID, binaryPartition, amount.Raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack splitPosition: %w", err)
+	}
+
+	return append(calls, MultiSendCall{To: spender, Data: data}), nil
+}
+
+// MergeCalls are the Safe calls turning amount of full YES/NO sets of
+// conditionID back into USDC.e.
+func (s *Safe) MergeCalls(ctx context.Context, conditionID common.Hash, amount Amount, negRisk bool) ([]MultiSendCall, error) {
+	if err := s.checkCondition(ctx, conditionID, amount); err != nil {
+		return nil, err
+	}
+
+	positions, err := s.wallet.Network.OutcomePositions(conditionID, negRisk)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []*big.Int{positions[0].ID, positions[1].ID}
+
+	balances, err := s.wallet.CTF().BalanceOfBatch(ctx, s.Address, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, balance := range balances {
+		if balance.Cmp(amount) < 0 {
+			return nil, fmt.Errorf("proxy holds %s of outcome %d, can't merge %s", balance, i, amount)
+		}
+	}
+
+	call := MultiSendCall{To: s.wallet.Network.Splitter(negRisk)}
+	if negRisk {
+		call.Data, err = negRiskAdapterABI.Pack("mergePositions", conditionID, amount.Raw)
+	} else {
+		call.Data, err = ctfABI.Pack("mergePositions", s.wallet.Network.USDC, common.Hash{}, conditionID, binaryPartition, amount.Raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack mergePositions: %w", err)
+	}
+
+	return []MultiSendCall{call}, nil
+}
+
+// Split has the Safe split amount of USDC.e, paid by the wallet's EOA.
+func (s *Safe) Split(ctx context.Context, conditionID common.Hash, amount Amount, negRisk bool) (*PendingTx, error) {
+	calls, err := s.SplitCalls(ctx, conditionID, amount, negRisk)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ExecuteBatch(ctx, calls)
+}
+
+// Merge has the Safe merge amount of full sets, paid by the wallet's EOA.
+func (s *Safe) Merge(ctx context.Context, conditionID common.Hash, amount Amount, negRisk bool) (*PendingTx, error) {
+	calls, err := s.MergeCalls(ctx, conditionID, amount, negRisk)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ExecuteBatch(ctx, calls)
+}
+
+// checkCondition rejects amounts of the wrong precision and conditions that
+// are not open binary conditions.
+func (s *Safe) checkCondition(ctx context.Context, conditionID common.Hash, amount Amount) error {
+	if amount.Decimals != USDCDecimals || amount.Raw == nil || amount.Raw.Sign() <= 0 {
+		return fmt.Errorf("invalid amount %s, want a positive USDC.e amount", amount)
+	}
+
+	ctf := s.wallet.CTF()
+
+	slots, err := ctf.OutcomeSlotCount(ctx, conditionID)
+	if err != nil {
+		return err
+	}
+	if slots != binaryOutcomes {
+		return fmt.Errorf("condition %s has %d outcomes, want %d", conditionID, slots, binaryOutcomes)
+	}
+
+	resolution, err := ctf.Resolution(ctx, conditionID)
+	if err != nil {
+		return err
+	}
+	if resolution.Resolved() {
+		return fmt.Errorf("condition %s is resolved, redeem instead", conditionID)
+	}
+
+	return nil
+}
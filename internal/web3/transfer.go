@@ -0,0 +1,132 @@
+package web3
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TransferResult is a mined deposit or withdrawal and the balances after it.
+type TransferResult struct {
+	Tx       *TxResult
+	Balances *TokenBalances
+}
+
+// Deposit transfers amount from the wallet's EOA into its Safe proxy and
+// waits for confirmations.
+func (t *ERC20) Deposit(ctx context.Context, amount Amount, confirmations uint64) (*TransferResult, error) {
+	proxyAddress, err := t.wallet.CreateProxyAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.checkBalance(ctx, t.wallet.Address, amount); err != nil {
+		return nil, err
+	}
+
+	pending, err := t.Transfer(ctx, common.HexToAddress(proxyAddress), amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.waitTransfer(ctx, pending, confirmations)
+}
+
+// Withdraw has the Safe proxy transfer amount to to, which must be the
+// wallet's EOA or in allowed. The EOA pays the gas.
+func (t *ERC20) Withdraw(ctx context.Context, to common.Address, amount Amount, allowed []common.Address, confirmations uint64) (*TransferResult, error) {
+	if to != t.wallet.Address && !slices.Contains(allowed, to) {
+		return nil, fmt.Errorf("withdrawal destination %s is not allowlisted", to)
+	}
+
+	safe, err := t.wallet.Safe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.checkBalance(ctx, safe.Address, amount); err != nil {
+		return nil, err
+	}
+
+	data, err := t.TransferData(to, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := safe.Execute(ctx, t.Address, nil, data, SafeCall)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.waitTransfer(ctx, pending, confirmations)
+}
+
+// LoadAllowlist reads the withdrawal destinations allowed besides the
+// wallet itself from path, one address per line; blank lines and lines
+// starting with # are skipped. A missing file is reported as
+// os.ErrNotExist.
+func LoadAllowlist(path string) ([]common.Address, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't open allowlist: %w", err)
+	}
+	defer file.Close()
+
+	var allowed []common.Address
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		address := strings.TrimSpace(scanner.Text())
+		if address == "" || strings.HasPrefix(address, "#") {
+			continue
+		}
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("%s:%d: invalid address %q", path, line, address)
+		}
+		allowed = append(allowed, common.HexToAddress(address))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error when read allowlist: %w", err)
+	}
+
+	return allowed, nil
+}
+
+func (t *ERC20) checkBalance(ctx context.Context, owner common.Address, amount Amount) error {
+	if err := t.checkDecimals(amount); err != nil {
+		return err
+	}
+	if amount.IsZero() {
+		return fmt.Errorf("nothing to transfer")
+	}
+
+	balance, err := t.BalanceOf(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("%s holds %s %s, can't transfer %s", owner, balance, t.Symbol, amount)
+	}
+
+	return nil
+}
+
+func (t *ERC20) waitTransfer(ctx context.Context, pending *PendingTx, confirmations uint64) (*TransferResult, error) {
+	result, err := pending.Wait(ctx, confirmations)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := t.Balances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransferResult{Tx: result, Balances: balances}, nil
+}
@@ -0,0 +1,224 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+func TestLoadAllowlist(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "allowlist.txt")
+	content := "# exchange deposit\n0x2c7536E3605D9C16a7a3D7b1898e529396a65c23\n\n  0x907c14d6cea8e8fc78dd3db152f0a93f43276b4d  \n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	allowed, err := LoadAllowlist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []common.Address{
+		common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"),
+		common.HexToAddress("0x907C14d6Cea8e8FC78dD3dB152F0a93f43276b4D"),
+	}
+	if len(allowed) != len(want) || allowed[0] != want[0] || allowed[1] != want[1] {
+		t.Errorf("allowlist %v, want %v", allowed, want)
+	}
+
+	invalid := filepath.Join(dir, "invalid.txt")
+	if err := os.WriteFile(invalid, []byte("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23,0x01\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAllowlist(invalid); err == nil {
+		t.Error("invalid allowlist was loaded")
+	}
+
+	if _, err := LoadAllowlist(filepath.Join(dir, "missing.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing allowlist: %v, want os.ErrNotExist", err)
+	}
+}
+
+// testTokenCode is a bare token: balanceOf(a) is storage slot a and
+// transfer moves amounts between slots, reverting when the sender's is
+// short.
+var testTokenCode = common.FromHex("0x" +
+	"60003560e01c" + // selector
+	"806370a0823114601e57" + // balanceOf
+	"63a9059cbb14602b57" + // transfer
+	"5b600080fd" + // revert
+	"5b6004355460005260206000f3" + // return slot[account]
+	"5b6024353354818110601957" + // revert if slot[caller] < amount
+	"8190033355" + // slot[caller] -= amount
+	"600435540160043555" + // slot[to] += amount
+	"600160005260206000f3") // return true
+
+// testSafeCode stands in for a Safe: nonce() is 0 and execTransaction
+// calls to with data, ignoring signatures.
+var testSafeCode = common.FromHex("0x" +
+	"60003560e01c63affed0e014603c57" + // nonce
+	"6044356004018035906020018190600037" + // copy data to memory
+	"6000600082600060006004355af1" + // call to
+	"15604757600160005260206000f3" + // revert on failure, return true
+	"5b600060005260206000f3" + // return 0
+	"5b600080fd")
+
+// minedOnSend mines every transaction into its own block as it is sent.
+type minedOnSend struct {
+	Backend
+	backend *simulated.Backend
+}
+
+func (m minedOnSend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := m.Backend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	m.backend.Commit()
+
+	return nil
+}
+
+// newTransferWallet is a wallet on a simulated chain with testTokenCode as
+// USDC.e and testSafeCode at its Safe, holding the given amounts in units.
+func newTransferWallet(t *testing.T, eoaUnits, safeUnits int64) *Wallet {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+
+	network := *Polygon
+	network.ChainID = big.NewInt(1337)
+	network.USDC = common.HexToAddress("0x7e57000000000000000000000000000000000001")
+
+	safe, err := network.ProxyAddress(ProxySafe, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		owner: {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))},
+		network.USDC: {Code: testTokenCode, Balance: new(big.Int), Storage: map[common.Hash]common.Hash{
+			common.BytesToHash(owner.Bytes()): common.BigToHash(big.NewInt(eoaUnits)),
+			common.BytesToHash(safe.Bytes()):  common.BigToHash(big.NewInt(safeUnits)),
+		}},
+		safe: {Code: testSafeCode, Balance: new(big.Int)},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	wallet := NewWithBackend(minedOnSend{Backend: backend.Client(), backend: backend}, NewKeySigner(key))
+	wallet.Network = &network
+
+	return wallet
+}
+
+func mustParse(t *testing.T, token *ERC20, s string) Amount {
+	t.Helper()
+
+	amount, err := token.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return amount
+}
+
+func TestDepositWithdraw(t *testing.T) {
+	fastPolling(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	wallet := newTransferWallet(t, 25_000_000, 5_000_000)
+	usdc := wallet.USDC()
+	payee := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+
+	deposit, err := usdc.Deposit(ctx, mustParse(t, usdc, "10"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deposit.Tx.Success() || deposit.Balances.EOA.String() != "15" || deposit.Balances.Proxy.String() != "15" {
+		t.Errorf("after deposit: eoa %s proxy %s, want 15 and 15", deposit.Balances.EOA, deposit.Balances.Proxy)
+	}
+
+	withdrawal, err := usdc.Withdraw(ctx, payee, mustParse(t, usdc, "4.5"), []common.Address{payee}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !withdrawal.Tx.Success() || withdrawal.Balances.EOA.String() != "15" || withdrawal.Balances.Proxy.String() != "10.5" {
+		t.Errorf("after withdrawal: eoa %s proxy %s, want 15 and 10.5", withdrawal.Balances.EOA, withdrawal.Balances.Proxy)
+	}
+
+	received, err := usdc.BalanceOf(ctx, payee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.String() != "4.5" {
+		t.Errorf("payee received %s, want 4.5", received)
+	}
+
+	// The EOA itself needs no allowlist.
+	back, err := usdc.Withdraw(ctx, wallet.Address, mustParse(t, usdc, "0.5"), nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Balances.EOA.String() != "15.5" || back.Balances.Proxy.String() != "10" {
+		t.Errorf("after withdrawal to self: eoa %s proxy %s, want 15.5 and 10", back.Balances.EOA, back.Balances.Proxy)
+	}
+}
+
+func TestTransferRejected(t *testing.T) {
+	ctx := context.Background()
+
+	wallet := newTransferWallet(t, 1_000_000, 2_000_000)
+	usdc := wallet.USDC()
+	payee := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	other := common.HexToAddress("0x907C14d6Cea8e8FC78dD3dB152F0a93f43276b4D")
+
+	tests := []struct {
+		name string
+		send func() (*TransferResult, error)
+	}{
+		{"not allowlisted", func() (*TransferResult, error) {
+			return usdc.Withdraw(ctx, other, mustParse(t, usdc, "1"), []common.Address{payee}, 1)
+		}},
+		{"no allowlist", func() (*TransferResult, error) {
+			return usdc.Withdraw(ctx, payee, mustParse(t, usdc, "1"), nil, 1)
+		}},
+		{"deposit above balance", func() (*TransferResult, error) {
+			return usdc.Deposit(ctx, mustParse(t, usdc, "1.000001"), 1)
+		}},
+		{"withdrawal above balance", func() (*TransferResult, error) {
+			return usdc.Withdraw(ctx, payee, mustParse(t, usdc, "2.5"), []common.Address{payee}, 1)
+		}},
+		{"zero", func() (*TransferResult, error) {
+			return usdc.Deposit(ctx, mustParse(t, usdc, "0"), 1)
+		}},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.send(); err == nil {
+			t.Errorf("%s: transfer went through", tt.name)
+		}
+	}
+
+	nonce, err := wallet.Client.PendingNonceAt(ctx, wallet.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 0 {
+		t.Errorf("%d txs sent for rejected transfers", nonce)
+	}
+}
@@ -0,0 +1,391 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrTxReverted = errors.New("transaction reverted")
+
+// TxRequest describes a transaction to send from the wallet. Zero GasLimit
+// means estimate it.
+type TxRequest struct {
+	To       *common.Address
+	Value    *big.Int
+	Data     []byte
+	GasLimit uint64
+}
+
+// BumpPolicy tells PendingTx.Wait to replace a transaction that is still
+// pending After it was sent, raising its fees by at least Percent (nodes
+// require 10) and never above MaxFeePerGas when set. After <= 0 disables
+// bumping.
+type BumpPolicy struct {
+	After        time.Duration
+	Percent      int64
+	MaxFeePerGas *big.Int
+}
+
+// due reports whether a transaction sent at sentAt should be replaced.
+func (b *BumpPolicy) due(sentAt time.Time) bool {
+	return b != nil && b.After > 0 && time.Since(sentAt) >= b.After
+}
+
+// PendingTx is a broadcast transaction that can be waited on. Tx is the
+// latest version sent, replaced ones are kept until one of them is mined.
+type PendingTx struct {
+	Tx       *types.Transaction
+	replaced []*types.Transaction
+	sentAt   time.Time
+	wallet   *Wallet
+}
+
+func (p *PendingTx) Hash() common.Hash {
+	return p.Tx.Hash()
+}
+
+// Wait blocks until the transaction or one of its replacements is mined
+// with the given number of confirmations. Every version sent stays a
+// candidate until then, so a reorg that swaps the mined version for another
+// one is followed, see Wallet.WaitMined. With Wallet.Bump set, it replaces
+// the transaction while none of them is mined.
+func (p *PendingTx) Wait(ctx context.Context, confirmations uint64) (*TxResult, error) {
+	if confirmations == 0 {
+		confirmations = 1
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var (
+		seen   = make(map[common.Hash]common.Hash)
+		reorgs int
+	)
+
+	for {
+		// Only bump when every version is known to be unmined.
+		mined := false
+
+		for _, tx := range append([]*types.Transaction{p.Tx}, p.replaced...) {
+			hash := tx.Hash()
+
+			result, err := p.wallet.checkMined(ctx, hash)
+			if err != nil {
+				log.Printf("error when check tx %s: %v", hash, err)
+				mined = true
+				continue
+			}
+
+			if result == nil {
+				if block, ok := seen[hash]; ok {
+					reorgs++
+					log.Printf("Tx %s was reorged out of block %s", hash, block)
+					delete(seen, hash)
+				}
+				continue
+			}
+
+			if block, ok := seen[hash]; ok && block != result.BlockHash {
+				reorgs++
+				log.Printf("Tx %s moved from block %s to %s", hash, block, result.BlockHash)
+			}
+			seen[hash] = result.BlockHash
+			result.Reorgs = reorgs
+			mined = true
+
+			if result.Confirmations < confirmations {
+				continue
+			}
+
+			p.Tx = tx
+
+			if !result.Success() {
+				return result, fmt.Errorf("%w: %s", ErrTxReverted, hash)
+			}
+
+			return result, nil
+		}
+
+		if bump := p.wallet.Bump; !mined && bump.due(p.sentAt) {
+			if err := p.bump(ctx, bump); err != nil {
+				log.Printf("can't bump tx %s: %v", p.Hash(), err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error when wait tx %s: %w", p.Hash(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *PendingTx) bump(ctx context.Context, policy *BumpPolicy) error {
+	fees, err := p.wallet.GetFees(ctx)
+	if err != nil {
+		return err
+	}
+
+	percent := policy.Percent
+	if percent < 10 {
+		percent = 10
+	}
+
+	tipCap := maxBig(bumpFee(p.Tx.GasTipCap(), percent), fees.TipCap)
+	maxFeePerGas := maxBig(bumpFee(p.Tx.GasFeeCap(), percent), fees.MaxFeePerGas)
+
+	if policy.MaxFeePerGas != nil && maxFeePerGas.Cmp(policy.MaxFeePerGas) > 0 {
+		p.sentAt = time.Now()
+		return fmt.Errorf("max fee %s would exceed cap %s", maxFeePerGas, policy.MaxFeePerGas)
+	}
+
+	replacement, err := p.wallet.ReplaceTx(ctx, p.Tx, tipCap, maxFeePerGas)
+	if err != nil {
+		p.sentAt = time.Now()
+		return err
+	}
+
+	log.Printf("Bumped tx %s -> %s", p.Hash(), replacement.Hash())
+
+	p.replaced = append(p.replaced, p.Tx)
+	p.Tx = replacement
+	p.sentAt = time.Now()
+
+	return nil
+}
+
+// ReplaceTx re-sends tx with the same nonce and new fees.
+func (w *Wallet) ReplaceTx(ctx context.Context, tx *types.Transaction, tipCap, maxFeePerGas *big.Int) (*types.Transaction, error) {
+	chainID, err := w.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     tx.Nonce(),
+		GasTipCap: tipCap,
+		GasFeeCap: maxFeePerGas,
+		Gas:       tx.Gas(),
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+	})
+
+	signedTx, err := w.Signer.SignTx(ctx, replacement, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.Client.SendTransaction(ctx, signedTx); err != nil && !isAlreadyKnown(err) {
+		return nil, fmt.Errorf("error when send replacement tx: %w", err)
+	}
+	w.Nonces.MarkSent(w.Address, tx.Nonce(), signedTx.Hash())
+
+	return signedTx, nil
+}
+
+func bumpFee(fee *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+
+	return b
+}
+
+// BuildTx assembles an unsigned DynamicFeeTx for req. The nonce is
+// reserved from w.Nonces, so the result must go to SendSigned or the nonce
+// must be released.
+func (w *Wallet) BuildTx(ctx context.Context, req TxRequest) (*types.Transaction, error) {
+	chainID, err := w.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := w.GetFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	value := req.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	gasLimit := req.GasLimit
+	if gasLimit == 0 {
+		gasLimit, err = w.GetGasLimit(ctx, ethereum.CallMsg{
+			From:      w.Address,
+			To:        req.To,
+			GasFeeCap: fees.MaxFeePerGas,
+			GasTipCap: fees.TipCap,
+			Value:     value,
+			Data:      req.Data,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nonce, err := w.Nonces.Reserve(ctx, w.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: fees.TipCap,
+		GasFeeCap: fees.MaxFeePerGas,
+		Gas:       gasLimit,
+		To:        req.To,
+		Value:     value,
+		Data:      req.Data,
+	})
+
+	return tx, nil
+}
+
+// SendTx builds, signs and broadcasts req. If the node says the nonce is
+// already taken, the nonce manager is resynced and the tx rebuilt.
+func (w *Wallet) SendTx(ctx context.Context, req TxRequest) (*PendingTx, error) {
+	const attempts = 3
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		var tx *types.Transaction
+		tx, err = w.BuildTx(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		var pending *PendingTx
+		pending, err = w.SendSigned(ctx, tx)
+		if err == nil {
+			return pending, nil
+		}
+
+		if !isNonceError(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// SendSigned signs an already built transaction and broadcasts it,
+// reporting the outcome of its nonce to w.Nonces.
+func (w *Wallet) SendSigned(ctx context.Context, tx *types.Transaction) (*PendingTx, error) {
+	chainID, err := w.ChainID(ctx)
+	if err != nil {
+		w.Nonces.Release(w.Address, tx.Nonce())
+		return nil, err
+	}
+
+	signedTx, err := w.Signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		w.Nonces.Release(w.Address, tx.Nonce())
+		return nil, err
+	}
+
+	err = w.Client.SendTransaction(ctx, signedTx)
+	switch {
+	case err == nil || isAlreadyKnown(err):
+		w.Nonces.MarkSent(w.Address, tx.Nonce(), signedTx.Hash())
+	case isNonceError(err):
+		w.Nonces.MarkSent(w.Address, tx.Nonce(), common.Hash{})
+		if syncErr := w.Nonces.Resync(ctx, w.Address); syncErr != nil {
+			return nil, syncErr
+		}
+		return nil, fmt.Errorf("error when send tx: %w", err)
+	default:
+		w.Nonces.Release(w.Address, tx.Nonce())
+		return nil, fmt.Errorf("error when send tx: %w", err)
+	}
+
+	return &PendingTx{
+		Tx:     signedTx,
+		sentAt: time.Now(),
+		wallet: w,
+	}, nil
+}
+
+// CancelNonce replaces whatever is pending at nonce with an empty transfer
+// to self, paying twice the current fees so it outbids the stuck tx.
+func (w *Wallet) CancelNonce(ctx context.Context, nonce uint64) (*PendingTx, error) {
+	fees, err := w.GetFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.sendSelfTx(ctx, nonce, bumpFee(fees.TipCap, 100), bumpFee(fees.MaxFeePerGas, 100))
+}
+
+// FillNonceGaps sends an empty transfer to self at every nonce left unused
+// by dropped transactions, so the ones queued after them can be mined.
+func (w *Wallet) FillNonceGaps(ctx context.Context) ([]*PendingTx, error) {
+	gaps, err := w.Nonces.TakeGaps(ctx, w.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(gaps) == 0 {
+		return nil, nil
+	}
+
+	fees, err := w.GetFees(ctx)
+	if err != nil {
+		for _, nonce := range gaps {
+			w.Nonces.Release(w.Address, nonce)
+		}
+		return nil, err
+	}
+
+	var sent []*PendingTx
+	for i, nonce := range gaps {
+		pending, err := w.sendSelfTx(ctx, nonce, fees.TipCap, fees.MaxFeePerGas)
+		if err != nil {
+			for _, rest := range gaps[i+1:] {
+				w.Nonces.Release(w.Address, rest)
+			}
+			return sent, err
+		}
+		sent = append(sent, pending)
+	}
+
+	return sent, nil
+}
+
+func (w *Wallet) sendSelfTx(ctx context.Context, nonce uint64, tipCap, maxFeePerGas *big.Int) (*PendingTx, error) {
+	chainID, err := w.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: maxFeePerGas,
+		Gas:       21000,
+		To:        &w.Address,
+		Value:     new(big.Int),
+	})
+
+	return w.SendSigned(ctx, tx)
+}
@@ -0,0 +1,207 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// flakyReceipts fails the first fails receipt lookups.
+type flakyReceipts struct {
+	Backend
+	fails atomic.Int32
+}
+
+func (f *flakyReceipts) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if f.fails.Add(-1) >= 0 {
+		return nil, errors.New("connection reset")
+	}
+
+	return f.Backend.TransactionReceipt(ctx, hash)
+}
+
+func TestBumpPolicyDue(t *testing.T) {
+	sentAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		policy *BumpPolicy
+		due    bool
+	}{
+		{"nil", nil, false},
+		{"zero", &BumpPolicy{}, false},
+		{"negative", &BumpPolicy{After: -time.Second}, false},
+		{"not yet", &BumpPolicy{After: time.Hour}, false},
+		{"due", &BumpPolicy{After: time.Second}, true},
+	}
+
+	for _, tt := range tests {
+		if due := tt.policy.due(sentAt); due != tt.due {
+			t.Errorf("%s: due %v, want %v", tt.name, due, tt.due)
+		}
+	}
+}
+
+func TestPendingTxBump(t *testing.T) {
+	ctx := context.Background()
+	wallet, backend := newSimWallet(t)
+	wallet.Gas = FixedGas{TipGwei: 1, MaxFeeGwei: 10}
+
+	pending, err := wallet.SendTx(ctx, TxRequest{To: &common.Address{0x02}, Value: big.NewInt(1), GasLimit: 21000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := pending.Tx
+
+	// The fee cap would be exceeded, so nothing is sent.
+	if err := pending.bump(ctx, &BumpPolicy{After: time.Nanosecond, MaxFeePerGas: Gwei(10.5)}); err == nil {
+		t.Fatal("bumped above the cap")
+	}
+	if pending.Tx != original {
+		t.Fatal("tx replaced although the bump failed")
+	}
+
+	// Percent below what nodes accept is raised to 10.
+	if err := pending.bump(ctx, &BumpPolicy{After: time.Nanosecond, Percent: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	replacement := pending.Tx
+	if replacement.Nonce() != original.Nonce() {
+		t.Fatalf("replacement nonce %d, want %d", replacement.Nonce(), original.Nonce())
+	}
+	if replacement.GasTipCap().Cmp(Gwei(1.1)) != 0 || replacement.GasFeeCap().Cmp(Gwei(11)) != 0 {
+		t.Errorf("replacement fees tip %s max %s, want 1.1 and 11 gwei", replacement.GasTipCap(), replacement.GasFeeCap())
+	}
+
+	backend.Commit()
+
+	result, err := pending.Wait(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Hash != replacement.Hash() {
+		t.Errorf("mined %s, want the replacement %s", result.Hash, replacement.Hash())
+	}
+	if _, err := backend.Client().TransactionReceipt(ctx, original.Hash()); !errors.Is(err, ethereum.NotFound) {
+		t.Errorf("replaced tx has a receipt: %v", err)
+	}
+}
+
+func TestPendingTxWaitRetries(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	wallet, backend := newSimWallet(t)
+
+	pending, err := wallet.SendTx(ctx, TxRequest{To: &common.Address{0x02}, Value: big.NewInt(1), GasLimit: 21000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	flaky := &flakyReceipts{Backend: wallet.Client}
+	flaky.fails.Store(1)
+	wallet.Client = flaky
+
+	result, err := pending.Wait(ctx, 1)
+	if err != nil {
+		t.Fatalf("wait gave up after a failed lookup: %v", err)
+	}
+	if result.Hash != pending.Hash() {
+		t.Errorf("mined %s, want %s", result.Hash, pending.Hash())
+	}
+}
+
+// failingSends rejects every transaction with err.
+type failingSends struct {
+	Backend
+	err error
+}
+
+func (f failingSends) SendTransaction(context.Context, *types.Transaction) error {
+	return f.err
+}
+
+func TestBuildTx(t *testing.T) {
+	ctx := context.Background()
+	wallet, _ := newSimWallet(t)
+	wallet.Gas = FixedGas{TipGwei: 2, MaxFeeGwei: 50}
+
+	to := common.Address{0x02}
+
+	tests := []struct {
+		name string
+		req  TxRequest
+		gas  uint64
+		// The node's estimate may overshoot by up to 1.5%.
+		slack uint64
+	}{
+		{"transfer", TxRequest{To: &to, Value: big.NewInt(5)}, 21000, 0},
+		// 16 gas per non-zero calldata byte.
+		{"calldata", TxRequest{To: &to, Data: []byte{1, 2, 3}}, 21048, 315},
+		{"fixed limit", TxRequest{To: &to, GasLimit: 100_000}, 100_000, 0},
+	}
+
+	for i, tt := range tests {
+		tx, err := wallet.BuildTx(ctx, tt.req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if tx.Type() != types.DynamicFeeTxType || tx.ChainId().Int64() != 1337 {
+			t.Errorf("%s: type %d on chain %s, want EIP-1559 on 1337", tt.name, tx.Type(), tx.ChainId())
+		}
+		if tx.GasTipCap().Cmp(Gwei(2)) != 0 || tx.GasFeeCap().Cmp(Gwei(50)) != 0 {
+			t.Errorf("%s: tip %s max fee %s, want 2 and 50 gwei", tt.name, tx.GasTipCap(), tx.GasFeeCap())
+		}
+		if tx.Gas() < tt.gas || tx.Gas() > tt.gas+tt.slack {
+			t.Errorf("%s: gas %d, want %d", tt.name, tx.Gas(), tt.gas)
+		}
+		if tx.Nonce() != uint64(i) {
+			t.Errorf("%s: nonce %d, want %d", tt.name, tx.Nonce(), i)
+		}
+		if tx.Value() == nil || (tt.req.Value == nil && tx.Value().Sign() != 0) {
+			t.Errorf("%s: value %v", tt.name, tx.Value())
+		}
+	}
+}
+
+func TestSendTxReleasesNonce(t *testing.T) {
+	ctx := context.Background()
+	wallet, backend := newSimWallet(t)
+
+	client := wallet.Client
+	wallet.Client = failingSends{Backend: client, err: errors.New("insufficient funds for gas * price + value")}
+
+	req := TxRequest{To: &common.Address{0x02}, Value: big.NewInt(1), GasLimit: 21000}
+	if _, err := wallet.SendTx(ctx, req); err == nil {
+		t.Fatal("send went through")
+	}
+
+	wallet.Client = client
+
+	pending, err := wallet.SendTx(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Tx.Nonce() != 0 {
+		t.Fatalf("nonce %d after a failed send, want 0 again", pending.Tx.Nonce())
+	}
+	backend.Commit()
+
+	result, err := pending.Wait(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success() {
+		t.Error("tx reverted")
+	}
+}
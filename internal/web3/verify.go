@@ -0,0 +1,131 @@
+package web3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// eip1271MagicValue is returned by isValidSignature(bytes32,bytes) for a
+// valid signature.
+var eip1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
+var eip1271ABI = mustABI(`[{"name":"isValidSignature","type":"function","stateMutability":"view","inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],"outputs":[{"name":"","type":"bytes4"}]}]`)
+
+// NormalizeV returns a copy of a 65 byte signature with V as 27/28, the form
+// every signer of this package produces.
+func NormalizeV(signature []byte) ([]byte, error) {
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+
+	sig := bytes.Clone(signature)
+	switch sig[64] {
+	case 0, 1:
+		sig[64] += 27
+	case 27, 28:
+	default:
+		return nil, fmt.Errorf("invalid signature v %d", sig[64])
+	}
+
+	return sig, nil
+}
+
+// RecoverHash returns the address that signed hash.
+func RecoverHash(hash, signature []byte) (common.Address, error) {
+	sig, err := NormalizeV(signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	sig[64] -= 27
+
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("can't recover signer: %w", err)
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+// RecoverPersonal returns the address that personal_signed data.
+func RecoverPersonal(data, signature []byte) (common.Address, error) {
+	return RecoverHash(personalHash(data), signature)
+}
+
+// RecoverTypedData returns the address that signed the EIP-712 data.
+func RecoverTypedData(data apitypes.TypedData, signature []byte) (common.Address, error) {
+	hash, err := typedDataHash(data)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return RecoverHash(hash, signature)
+}
+
+func VerifyPersonal(address common.Address, data, signature []byte) error {
+	signer, err := RecoverPersonal(data, signature)
+	if err != nil {
+		return err
+	}
+
+	if signer != address {
+		return fmt.Errorf("message signed by %s, expected %s", signer, address)
+	}
+
+	return nil
+}
+
+func VerifyTypedData(address common.Address, data apitypes.TypedData, signature []byte) error {
+	signer, err := RecoverTypedData(data, signature)
+	if err != nil {
+		return err
+	}
+
+	if signer != address {
+		return fmt.Errorf("typed data signed by %s, expected %s", signer, address)
+	}
+
+	return nil
+}
+
+// IsValidSignature asks the contract wallet at contract whether signature
+// is valid for hash, as defined by EIP-1271.
+func (w *Wallet) IsValidSignature(ctx context.Context, contract common.Address, hash common.Hash, signature []byte) (bool, error) {
+	data, err := eip1271ABI.Pack("isValidSignature", hash, signature)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack isValidSignature: %w", err)
+	}
+
+	res, err := w.Client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		// Safes revert on invalid signatures instead of returning a value.
+		if strings.Contains(err.Error(), "revert") {
+			return false, nil
+		}
+		return false, fmt.Errorf("error when call isValidSignature: %w", err)
+	}
+
+	if len(res) < 4 {
+		return false, nil
+	}
+
+	return bytes.Equal(res[:4], eip1271MagicValue[:]), nil
+}
+
+// VerifyProxySignature checks signature for hash against the wallet's Safe
+// proxy. The Safe validates the owner's signature of the SafeMessage that
+// wraps hash, not of hash itself.
+func (w *Wallet) VerifyProxySignature(ctx context.Context, hash common.Hash, signature []byte) (bool, error) {
+	proxyAddress, err := w.CreateProxyAddress()
+	if err != nil {
+		return false, err
+	}
+
+	return w.IsValidSignature(ctx, common.HexToAddress(proxyAddress), hash, signature)
+}
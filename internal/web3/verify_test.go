@@ -0,0 +1,191 @@
+package web3
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// testEIP1271Code is a contract wallet whose isValidSignature(hash, sig)
+// accepts signatures of hash by the owner in slot 0 and reverts otherwise,
+// like a Safe.
+var testEIP1271Code = common.FromHex("0x" +
+	"600435600052" + // mem[0] = hash
+	"602435600401" + // offset of sig
+	"806060013560f81c602052" + // mem[32] = v
+	"8060200135604052" + // mem[64] = r
+	"60400135606052" + // mem[96] = s
+	"602060806080600060015afa50" + // mem[128] = ecrecover(mem[:128])
+	"60805160005414604157" + // signer == owner
+	"600080fd" + // revert
+	"5b631626ba7e60e01b60005260206000f3") // return the magic value
+
+func TestNormalizeV(t *testing.T) {
+	tests := []struct {
+		v    byte
+		want byte
+		ok   bool
+	}{
+		{0, 27, true},
+		{1, 28, true},
+		{27, 27, true},
+		{28, 28, true},
+		{2, 0, false},
+		{29, 0, false},
+		{31, 0, false},
+	}
+
+	for _, tt := range tests {
+		signature := make([]byte, 65)
+		signature[64] = tt.v
+
+		sig, err := NormalizeV(signature)
+		if (err == nil) != tt.ok {
+			t.Errorf("v %d: error %v, want ok %v", tt.v, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		if sig[64] != tt.want {
+			t.Errorf("v %d normalized to %d, want %d", tt.v, sig[64], tt.want)
+		}
+		if signature[64] != tt.v {
+			t.Errorf("v %d: input modified", tt.v)
+		}
+	}
+
+	if _, err := NormalizeV(make([]byte, 64)); err == nil {
+		t.Error("normalized a 64 byte signature")
+	}
+}
+
+func TestRecoverRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewKeySigner(key)
+
+	data := []byte("polymarket")
+	signature, err := signer.SignPersonal(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := signature[64]; v != 27 && v != 28 {
+		t.Fatalf("personal_sign v %d, want 27/28", v)
+	}
+
+	// Signatures with V as 0/1 recover the same signer.
+	raw := append([]byte(nil), signature...)
+	raw[64] -= 27
+	for _, sig := range [][]byte{signature, raw} {
+		if err := VerifyPersonal(signer.Address(), data, sig); err != nil {
+			t.Errorf("v %d: %v", sig[64], err)
+		}
+	}
+
+	if err := VerifyPersonal(signer.Address(), []byte("polymarket!"), signature); err == nil {
+		t.Error("signature verified for other data")
+	}
+
+	typed, err := NewTypedData(SafeDomain(big.NewInt(137), common.Address{0x01}), "SafeTx", NewSafeTx(common.Address{0x02}, nil, nil, SafeCall, big.NewInt(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err = signer.SignTypedData(ctx, typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address, err := RecoverTypedData(typed, signature); err != nil || address != signer.Address() {
+		t.Errorf("typed data recovered %s (%v), want %s", address, err, signer.Address())
+	}
+}
+
+func TestSignMsg(t *testing.T) {
+	wallet, _ := newSimWallet(t)
+
+	// The SIWE message of account creation goes through SignMsg.
+	message := "clob.polymarket.com wants you to sign in with your Ethereum account:\n" + wallet.Address.Hex()
+
+	signature, err := wallet.SignMsg(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := sig[64]; v != 27 && v != 28 {
+		t.Errorf("v %d, want 27/28", v)
+	}
+	if err := VerifyPersonal(wallet.Address, []byte(message), sig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIsValidSignature(t *testing.T) {
+	ctx := context.Background()
+
+	owner, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contract := common.HexToAddress("0x1271000000000000000000000000000000000001")
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		contract: {Code: testEIP1271Code, Balance: new(big.Int), Storage: map[common.Hash]common.Hash{
+			{}: common.BytesToHash(crypto.PubkeyToAddress(owner.PublicKey).Bytes()),
+		}},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	wallet := NewWithBackend(backend.Client(), NewKeySigner(owner))
+	hash := crypto.Keccak256Hash([]byte("order"))
+
+	sign := func(key *ecdsa.PrivateKey) []byte {
+		signature, err := crypto.Sign(hash.Bytes(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature[64] += 27
+		return signature
+	}
+
+	tests := []struct {
+		name      string
+		address   common.Address
+		signature []byte
+		valid     bool
+	}{
+		{"owner", contract, sign(owner), true},
+		{"other key", contract, sign(other), false},
+		{"garbage", contract, make([]byte, 65), false},
+		{"no contract", common.Address{0x02}, sign(owner), false},
+	}
+
+	for _, tt := range tests {
+		valid, err := wallet.IsValidSignature(ctx, tt.address, hash, tt.signature)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if valid != tt.valid {
+			t.Errorf("%s: valid %v, want %v", tt.name, valid, tt.valid)
+		}
+	}
+}
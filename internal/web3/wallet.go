@@ -7,39 +7,109 @@ import (
 	"fmt"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"math/big"
+	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 type Wallet struct {
-	Client     *ethclient.Client
-	PrivateKey *ecdsa.PrivateKey
-	Address    common.Address
+	Client  Backend
+	Signer  Signer
+	Address common.Address
+	Network *Network
+	Nonces  *NonceManager
+	Gas     GasStrategy
+	// Bump replaces transactions that stay pending too long, nil disables it.
+	Bump *BumpPolicy
+
+	mu      sync.Mutex
+	chainID *big.Int
 }
 
 func New(rpc string, privateKey string) (*Wallet, error) {
-	client, err := ethclient.Dial(rpc)
+	pKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
 	if err != nil {
-		return nil, fmt.Errorf("can't connect to RPC: %w", err)
+		return nil, fmt.Errorf("can't convert private key: %w", err)
 	}
 
-	pKey, err := crypto.HexToECDSA(privateKey[2:])
+	return NewFromKey(rpc, pKey)
+}
+
+func NewFromKey(rpc string, pKey *ecdsa.PrivateKey) (*Wallet, error) {
+	return NewWithSigner(rpc, NewKeySigner(pKey))
+}
+
+func NewWithSigner(rpc string, signer Signer) (*Wallet, error) {
+	client, err := Dial(rpc)
 	if err != nil {
-		return nil, fmt.Errorf("can't convert private key: %w", err)
+		return nil, err
+	}
+
+	return NewWithBackend(client, signer), nil
+}
+
+func NewWithBackend(client Backend, signer Signer) *Wallet {
+	return &Wallet{
+		Client:  client,
+		Signer:  signer,
+		Address: signer.Address(),
+		Network: Polygon,
+		Nonces:  NewNonceManager(client),
+		Gas:     SuggestedGas{},
+	}
+}
+
+func (w *Wallet) ChainID(ctx context.Context) (*big.Int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.chainID != nil {
+		return w.chainID, nil
+	}
+
+	chainID, err := w.Client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting chain id: %w", err)
+	}
+	w.chainID = chainID
+
+	return chainID, nil
+}
+
+// SelectNetwork sets w.Network to the Polymarket contracts of the node's
+// chain. NewWithBackend assumes Polygon until this is called.
+func (w *Wallet) SelectNetwork(ctx context.Context) error {
+	chainID, err := w.ChainID(ctx)
+	if err != nil {
+		return err
 	}
 
-	wallet := &Wallet{
-		Client:     client,
-		PrivateKey: pKey,
-		Address:    crypto.PubkeyToAddress(pKey.PublicKey),
+	network, err := NetworkByChainID(chainID)
+	if err != nil {
+		return err
 	}
+	w.Network = network
 
-	return wallet, nil
+	return nil
+}
+
+// CheckNetwork makes sure the node is on w.Network's chain, so nothing is
+// signed for one chain with another chain's contracts.
+func (w *Wallet) CheckNetwork(ctx context.Context) error {
+	chainID, err := w.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if chainID.Cmp(w.Network.ChainID) != 0 {
+		return fmt.Errorf("node is on chain %s, but the wallet uses %s (chain %s)", chainID, w.Network.Name, w.Network.ChainID)
+	}
+
+	return nil
 }
 
 func (w *Wallet) GetNonce(ctx context.Context) (uint64, error) {
@@ -85,6 +155,10 @@ func (w *Wallet) GetMaxFeePerGas(ctx context.Context) (*big.Int, error) {
 	return maxFeePerGas, nil
 }
 
+func (w *Wallet) GetFees(ctx context.Context) (*Fees, error) {
+	return w.Gas.Fees(ctx, w.Client)
+}
+
 func (w *Wallet) GetGasLimit(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
 	gasLimit, err := w.Client.EstimateGas(ctx, msg)
 	if err != nil {
@@ -95,12 +169,13 @@ func (w *Wallet) GetGasLimit(ctx context.Context, msg ethereum.CallMsg) (uint64,
 }
 
 func (w *Wallet) SignMsg(data string) (string, error) {
-	msg := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)
-	hash := crypto.Keccak256Hash([]byte(msg))
-
-	signature, err := crypto.Sign(hash.Bytes(), w.PrivateKey)
+	signature, err := w.Signer.SignPersonal(context.Background(), []byte(data))
 	if err != nil {
-		return "", fmt.Errorf("error when sign signature: %w", err)
+		return "", err
+	}
+
+	if err := VerifyPersonal(w.Address, []byte(data), signature); err != nil {
+		return "", fmt.Errorf("signature does not verify: %w", err)
 	}
 
 	hexSignature := hexutil.Encode(signature)
@@ -108,37 +183,17 @@ func (w *Wallet) SignMsg(data string) (string, error) {
 	return hexSignature, nil
 }
 
+// CreateProxyAddress returns the address of the wallet's Safe proxy.
 func (w *Wallet) CreateProxyAddress() (string, error) {
-	addressType, err := abi.NewType("address", "", nil)
+	address, err := w.ProxyAddress(ProxySafe)
 	if err != nil {
-		return "", fmt.Errorf("failed to create address type: %w", err)
-	}
-
-	arguments := abi.Arguments{
-		{
-			Type: addressType,
-		},
+		return "", err
 	}
 
-	abiEncoded, err := arguments.Pack(w.Address)
-	if err != nil {
-		return "", fmt.Errorf("failed to ABI encode address: %w", err)
-	}
-
-	hash := crypto.Keccak256(abiEncoded)
-
-	safeFactoryAddress := common.HexToAddress("0xaacFeEa03eb1561C4e67d661e40682Bd20E3541b")
-	safeInitCodeHash := common.FromHex("0x2bce2127ff07fb632d16c8347c4ebf501f4841168bed00d9e6ef715ddb6fcecf")
-
-	create2Address := crypto.CreateAddress2(
-		safeFactoryAddress,
-		common.BytesToHash(hash),
-		safeInitCodeHash,
-	)
-
-	return create2Address.String(), nil
+	return address.String(), nil
 }
 
+// SignTypedMsg signs typed data given as EIP-712 JSON.
 func (w *Wallet) SignTypedMsg(data string) (string, error) {
 	var tData apitypes.TypedData
 	err := json.Unmarshal([]byte(data), &tData)
@@ -146,25 +201,5 @@ func (w *Wallet) SignTypedMsg(data string) (string, error) {
 		return "", fmt.Errorf("failed to unmarshal typed data: %v", err)
 	}
 
-	typedDataHash, err := tData.HashStruct(tData.PrimaryType, tData.Message)
-	if err != nil {
-		return "", fmt.Errorf("failed to hash typed data: %v", err)
-	}
-
-	domainSeparator, err := tData.HashStruct("EIP712Domain", tData.Domain.Map())
-	if err != nil {
-		return "", fmt.Errorf("failed to hash typed data: %v", err)
-	}
-
-	rawData := []byte(fmt.Sprintf("\x19\x01%s%s", string(domainSeparator), string(typedDataHash)))
-	challenge := crypto.Keccak256(rawData)
-	s, err := crypto.Sign(challenge, w.PrivateKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to sign message: %v", err)
-	}
-	s[64] += 27
-
-	hexSignature := hexutil.Encode(s)
-
-	return hexSignature, nil
+	return w.SignTypedData(context.Background(), tData)
 }
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+const PassphraseEnv = "POLY_KEYSTORE_PASSWORD"
+
+// SignerTokenEnv holds the bearer token shared by cmd/signer and the app.
+const SignerTokenEnv = "POLY_SIGNER_TOKEN"
+
+// ReadPassphrase takes the keystore passphrase from PassphraseEnv and falls
+// back to an interactive prompt without echo.
+func ReadPassphrase(prompt string) (string, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		return passphrase, nil
+	}
+
+	passphrase, err := ReadSecret(prompt)
+	if err != nil {
+		return "", fmt.Errorf("%w (or set %s)", err, PassphraseEnv)
+	}
+
+	return passphrase, nil
+}
+
+func ReadSecret(prompt string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", errors.New("stdin is not a terminal, can't prompt for secret")
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("error when read secret: %w", err)
+	}
+
+	return string(secret), nil
+}